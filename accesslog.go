@@ -0,0 +1,268 @@
+package http200ok
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AccessLogFormat int
+
+const (
+	LogText AccessLogFormat = iota
+	LogJSON
+	LogCombined
+)
+
+type AccessLogConfig struct {
+	Format AccessLogFormat
+	// Output defaults to os.Stderr.
+	Output io.Writer
+	// Handler sends the entries to log/slog, Format and Output are
+	// ignored when it is set.
+	Handler slog.Handler
+	// Exclude lists paths that are not logged, entries ending with "*"
+	// match a prefix.
+	Exclude []string
+	// SampleRate logs the given fraction of requests, server errors are
+	// always logged. Zero logs everything.
+	SampleRate float64
+}
+
+type AccessLogEntry struct {
+	Time      time.Time
+	Method    string
+	Path      string
+	Route     string
+	Proto     string
+	Status    int
+	Bytes     int
+	Latency   time.Duration
+	RemoteIP  string
+	UserAgent string
+	Referer   string
+	RequestID string
+}
+
+func AccessLog(config AccessLogConfig) Handler {
+
+	output := config.Output
+
+	if output == nil {
+
+		output = os.Stderr
+	}
+
+	var write func(c *Context, entry *AccessLogEntry)
+
+	switch {
+	case config.Handler != nil:
+
+		write = slogWriter(slog.New(config.Handler))
+
+	case config.Format == LogJSON:
+
+		write = slogWriter(slog.New(slog.NewJSONHandler(output, nil)))
+
+	case config.Format == LogCombined:
+
+		var mutex sync.Mutex
+
+		write = func(_ *Context, entry *AccessLogEntry) {
+
+			line := entry.combined()
+
+			mutex.Lock()
+
+			io.WriteString(output, line)
+
+			mutex.Unlock()
+		}
+
+	default:
+
+		write = slogWriter(slog.New(slog.NewTextHandler(output, nil)))
+	}
+
+	return func(c *Context) {
+
+		if excluded(config.Exclude, c.Request.URL.Path) {
+
+			return
+		}
+
+		start := time.Now()
+
+		// the entry is written once the error handler and the recovery
+		// have settled the response.
+		c.onFinish(func() {
+
+			status := c.Response.Status()
+
+			if config.SampleRate > 0 && config.SampleRate < 1 && status < http.StatusInternalServerError && rand.Float64() >= config.SampleRate {
+
+				return
+			}
+
+			write(c, &AccessLogEntry{
+				Time:      start,
+				Method:    c.Request.Method,
+				Path:      c.Request.URL.Path,
+				Route:     c.Route(),
+				Proto:     c.Request.Proto,
+				Status:    status,
+				Bytes:     c.Response.Size(),
+				Latency:   time.Since(start),
+				RemoteIP:  remoteIP(c.Request),
+				UserAgent: c.Request.UserAgent(),
+				Referer:   c.Request.Referer(),
+				RequestID: c.RequestID(),
+			})
+		})
+	}
+}
+
+func slogWriter(logger *slog.Logger) func(c *Context, entry *AccessLogEntry) {
+
+	return func(c *Context, entry *AccessLogEntry) {
+
+		level := slog.LevelInfo
+
+		if entry.Status >= http.StatusInternalServerError {
+
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(c.context(), level, "access",
+			slog.String("method", entry.Method),
+			slog.String("path", entry.Path),
+			slog.String("route", entry.Route),
+			slog.Int("status", entry.Status),
+			slog.Int("bytes", entry.Bytes),
+			slog.Duration("latency", entry.Latency),
+			slog.String("remote_ip", entry.RemoteIP),
+			slog.String("user_agent", entry.UserAgent),
+			slog.String("request_id", entry.RequestID),
+		)
+	}
+}
+
+// combined formats the entry in the Apache combined log format.
+func (e *AccessLogEntry) combined() string {
+
+	bytes := "-"
+
+	if e.Bytes > 0 {
+
+		bytes = fmt.Sprint(e.Bytes)
+	}
+
+	return fmt.Sprintf("%s - - [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		e.RemoteIP,
+		e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		logEscape(e.Method),
+		logEscape(e.Path),
+		logEscape(e.Proto),
+		e.Status,
+		bytes,
+		logEscape(orDash(e.Referer)),
+		logEscape(orDash(e.UserAgent)),
+	)
+}
+
+// logEscape escapes a quoted field the way Apache does, so a request
+// cannot break out of its line.
+func logEscape(value string) string {
+
+	var buf strings.Builder
+
+	for i := 0; i < len(value); i++ {
+
+		switch b := value[i]; b {
+		case '"', '\\':
+
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+
+		case '\b':
+
+			buf.WriteString(`\b`)
+
+		case '\n':
+
+			buf.WriteString(`\n`)
+
+		case '\r':
+
+			buf.WriteString(`\r`)
+
+		case '\t':
+
+			buf.WriteString(`\t`)
+
+		case '\v':
+
+			buf.WriteString(`\v`)
+
+		default:
+
+			if b < 0x20 || b >= 0x7f {
+
+				fmt.Fprintf(&buf, `\x%02x`, b)
+
+			} else {
+
+				buf.WriteByte(b)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+func orDash(value string) string {
+
+	if value == "" {
+
+		return "-"
+	}
+
+	return value
+}
+
+func excluded(patterns []string, path string) bool {
+
+	for _, pattern := range patterns {
+
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+
+			if strings.HasPrefix(path, prefix) {
+
+				return true
+			}
+
+		} else if pattern == path {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func remoteIP(req *http.Request) string {
+
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+
+		return host
+	}
+
+	return req.RemoteAddr
+}
@@ -0,0 +1,176 @@
+package http200ok
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func accessLogServer(config AccessLogConfig) *Server {
+
+	app := New()
+	app.Use(AccessLog(config), RequestID(RequestIDConfig{}))
+	app.Get("/users/:UserID/", func(c *Context) {
+
+		c.String(http.StatusOK, "Hello")
+	})
+
+	app.Get("/health/", func(c *Context) {})
+
+	app.Get("/fail/", func(c *Context) {
+
+		c.Status(http.StatusInternalServerError)
+	})
+
+	return app
+}
+
+func accessLogRequest(app *Server, path string) {
+
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("User-Agent", "tester")
+	req.Header.Set("X-Request-ID", "req-1")
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAccessLogText(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	accessLogRequest(accessLogServer(AccessLogConfig{Output: &buf}), "/users/42/")
+
+	for _, field := range []string{
+		"method=GET",
+		"path=/users/42/",
+		"route=/users/:UserID/",
+		"status=200",
+		"bytes=5",
+		"latency=",
+		"remote_ip=192.0.2.1",
+		"user_agent=tester",
+		"request_id=req-1",
+	} {
+
+		assert.Contains(t, buf.String(), field)
+	}
+}
+
+func TestAccessLogJSON(t *testing.T) {
+
+	var (
+		buf   bytes.Buffer
+		entry map[string]interface{}
+	)
+
+	accessLogRequest(accessLogServer(AccessLogConfig{Format: LogJSON, Output: &buf}), "/users/42/")
+
+	if assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry)) {
+
+		assert.Equal(t, "/users/:UserID/", entry["route"])
+		assert.Equal(t, float64(200), entry["status"])
+		assert.Equal(t, float64(5), entry["bytes"])
+		assert.Equal(t, "req-1", entry["request_id"])
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	accessLogRequest(accessLogServer(AccessLogConfig{Format: LogCombined, Output: &buf}), "/users/42/")
+
+	line := buf.String()
+
+	assert.True(t, strings.HasPrefix(line, "192.0.2.1 - - ["), line)
+	assert.True(t, strings.HasSuffix(line, `] "GET /users/42/ HTTP/1.1" 200 5 "-" "tester"`+"\n"), line)
+}
+
+func TestAccessLogCombinedEscape(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	app := New()
+	app.Use(AccessLog(AccessLogConfig{Format: LogCombined, Output: &buf}))
+
+	req := httptest.NewRequest("GET", "/a%0A1.2.3.4%20-%20-%20[x]%20%22GET%C3%A9", nil)
+	req.Header.Set("User-Agent", "tester\"\t")
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+
+	assert.Equal(t, 1, strings.Count(line, "\n"), line)
+	assert.True(t, strings.HasSuffix(line, `] "GET /a\n1.2.3.4 - - [x] \"GET\xc3\xa9 HTTP/1.1" 404 19 "-" "tester\"\t"`+"\n"), line)
+}
+
+func TestAccessLogSlogHandler(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	app := accessLogServer(AccessLogConfig{Handler: slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError})})
+
+	accessLogRequest(app, "/users/42/")
+
+	assert.Empty(t, buf.String())
+
+	accessLogRequest(app, "/fail/")
+
+	assert.Contains(t, buf.String(), `"level":"ERROR"`)
+}
+
+func TestAccessLogExcludeAndSampling(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	app := accessLogServer(AccessLogConfig{
+		Output:     &buf,
+		Exclude:    []string{"/health/", "/users/*"},
+		SampleRate: 0.000001,
+	})
+
+	accessLogRequest(app, "/health/")
+	accessLogRequest(app, "/users/42/")
+
+	assert.Empty(t, buf.String())
+
+	accessLogRequest(app, "/fail/")
+
+	assert.Contains(t, buf.String(), "status=500")
+}
+
+func TestAccessLogErrorAndPanic(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	app := New()
+	app.Use(AccessLog(AccessLogConfig{Format: LogCombined, Output: &buf}))
+	app.Get("/err", Wrap(func(c *Context) error {
+
+		return NewHTTPError(http.StatusNotFound, "", nil)
+	}))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/err", nil))
+
+	assert.Contains(t, buf.String(), `"GET /err HTTP/1.1" 404 `)
+
+	buf.Reset()
+
+	app = New()
+	app.Use(AccessLog(AccessLogConfig{Format: LogCombined, Output: &buf, SampleRate: 0.000001}))
+	app.Get("/panic", func(c *Context) {
+
+		panic("AAA")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+
+	assert.Contains(t, buf.String(), `"GET /panic HTTP/1.1" 500 `)
+}
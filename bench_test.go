@@ -0,0 +1,91 @@
+package http200ok
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type benchResponseWriter struct {
+	header http.Header
+}
+
+func (w *benchResponseWriter) Header() http.Header {
+
+	return w.header
+}
+
+func (w *benchResponseWriter) Write(data []byte) (int, error) {
+
+	return len(data), nil
+}
+
+func (w *benchResponseWriter) WriteHeader(int) {}
+
+func benchmarkRequest(b *testing.B, app *Server, path string) {
+
+	var (
+		rw  = &benchResponseWriter{header: make(http.Header)}
+		req = httptest.NewRequest("GET", path, nil)
+	)
+
+	app.Build()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+
+		app.ServeHTTP(rw, req)
+	}
+}
+
+func BenchmarkStaticRoute(b *testing.B) {
+
+	app := New()
+	app.Get("/users/", func(c *Context) {
+
+		c.Status(http.StatusOK)
+	})
+
+	benchmarkRequest(b, app, "/users/")
+}
+
+func BenchmarkParamRoute(b *testing.B) {
+
+	app := New()
+	app.Get("/users/:UserID/posts/:PostID/", func(c *Context) {
+
+		c.RequestParam("UserID")
+		c.RequestParam("PostID")
+		c.Status(http.StatusOK)
+	})
+
+	benchmarkRequest(b, app, "/users/42/posts/1/")
+}
+
+func BenchmarkMiddlewareRoute(b *testing.B) {
+
+	app := New()
+
+	for i := 0; i < 10; i++ {
+
+		app.Use(func(c *Context) {
+
+			c.Next()
+		})
+	}
+
+	admin := app.Group("/admin", func(c *Context) {
+
+		c.Set("CurrentUser", 1)
+	})
+
+	admin.Get("/users/:UserID/", func(c *Context) {
+
+		c.Get("CurrentUser")
+		c.Status(http.StatusOK)
+	})
+
+	benchmarkRequest(b, app, "/admin/users/42/")
+}
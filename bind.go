@@ -0,0 +1,405 @@
+package http200ok
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const defaultMaxBodySize = 10 << 20
+
+type BindError struct {
+	Code int
+	Err  error
+}
+
+func (e *BindError) Error() string {
+
+	return fmt.Sprintf("bind: %v", e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+
+	return e.Err
+}
+
+func (c *Context) Bind(v interface{}) error {
+
+	return c.bind(v, c.bindRequest)
+}
+
+func (c *Context) BindJSON(v interface{}) error {
+
+	return c.bind(v, c.bindJSON)
+}
+
+func (c *Context) BindXML(v interface{}) error {
+
+	return c.bind(v, c.bindXML)
+}
+
+func (c *Context) BindQuery(v interface{}) error {
+
+	return c.bind(v, c.bindQuery)
+}
+
+func (c *Context) BindForm(v interface{}) error {
+
+	return c.bind(v, c.bindForm)
+}
+
+func (c *Context) BindParams(v interface{}) error {
+
+	return c.bind(v, c.bindParams)
+}
+
+func (c *Context) bind(v interface{}, decode func(v interface{}) error) error {
+
+	if err := decode(v); err != nil {
+
+		return err
+	}
+
+	return Validate(v)
+}
+
+func (c *Context) bindRequest(v interface{}) error {
+
+	// path params only map onto struct fields, slices and maps are left
+	// to the body decoder.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Struct {
+
+		if err := c.bindParams(v); err != nil {
+
+			return err
+		}
+	}
+
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+
+		switch c.Request.Method {
+		case "GET", "HEAD", "DELETE", "OPTIONS":
+
+			return c.bindQuery(v)
+		}
+	}
+
+	contentType, _, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+
+	if err != nil {
+
+		return &BindError{Code: http.StatusUnsupportedMediaType, Err: err}
+	}
+
+	switch contentType {
+	case "application/json":
+
+		return c.bindJSON(v)
+
+	case "application/xml", "text/xml":
+
+		return c.bindXML(v)
+
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+
+		return c.bindForm(v)
+	}
+
+	return &BindError{Code: http.StatusUnsupportedMediaType, Err: fmt.Errorf("unsupported content type %q", contentType)}
+}
+
+func (c *Context) bindJSON(v interface{}) error {
+
+	if err := json.NewDecoder(c.body()).Decode(v); err != nil {
+
+		return bodyError(err)
+	}
+
+	return nil
+}
+
+func (c *Context) bindXML(v interface{}) error {
+
+	if err := xml.NewDecoder(c.body()).Decode(v); err != nil {
+
+		return bodyError(err)
+	}
+
+	return nil
+}
+
+func (c *Context) bindQuery(v interface{}) error {
+
+	return bindValues(v, "query", c.Request.URL.Query(), nil)
+}
+
+func (c *Context) bindForm(v interface{}) error {
+
+	c.body()
+
+	contentType, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+
+	if contentType == "multipart/form-data" {
+
+		if err := c.Request.ParseMultipartForm(c.server.maxBodySize); err != nil {
+
+			return bodyError(err)
+		}
+
+		return bindValues(v, "form", c.Request.MultipartForm.Value, c.Request.MultipartForm.File)
+	}
+
+	if err := c.Request.ParseForm(); err != nil {
+
+		return bodyError(err)
+	}
+
+	return bindValues(v, "form", c.Request.PostForm, nil)
+}
+
+func (c *Context) bindParams(v interface{}) error {
+
+	values := make(map[string][]string, len(c.params))
+
+	for _, param := range c.params {
+
+		values[param.Key] = []string{param.Value}
+	}
+
+	return bindValues(v, "param", values, nil)
+}
+
+func (c *Context) body() io.ReadCloser {
+
+	if !c.limited {
+
+		body := c.Request.Body
+
+		if body == nil {
+
+			body = http.NoBody
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Response, body, c.server.maxBodySize)
+		c.limited = true
+	}
+
+	return c.Request.Body
+}
+
+func bodyError(err error) error {
+
+	var maxBytesErr *http.MaxBytesError
+
+	if errors.As(err, &maxBytesErr) {
+
+		return &BindError{Code: http.StatusRequestEntityTooLarge, Err: err}
+	}
+
+	return &BindError{Code: http.StatusBadRequest, Err: err}
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func bindValues(v interface{}, tag string, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+
+		return fmt.Errorf("http200ok: bind target must be a non-nil pointer to a struct, got %T", v)
+	}
+
+	return bindStruct(rv.Elem(), tag, values, files)
+}
+
+func bindStruct(rv reflect.Value, tag string, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+
+		field := rt.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous {
+
+			continue
+		}
+
+		name := field.Tag.Get(tag)
+
+		if name == "-" {
+
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if field.Anonymous && name == "" && fv.Kind() == reflect.Struct {
+
+			if err := bindStruct(fv, tag, values, files); err != nil {
+
+				return err
+			}
+
+			continue
+		}
+
+		if name == "" {
+
+			continue
+		}
+
+		if headers, found := files[name]; found {
+
+			switch {
+			case field.Type == fileHeaderType:
+
+				fv.Set(reflect.ValueOf(headers[0]))
+
+			case field.Type.Kind() == reflect.Slice && field.Type.Elem() == fileHeaderType:
+
+				fv.Set(reflect.ValueOf(headers))
+			}
+
+			continue
+		}
+
+		raw, found := values[name]
+
+		if !found || len(raw) == 0 {
+
+			continue
+		}
+
+		if err := setField(fv, raw); err != nil {
+
+			return &BindError{Code: http.StatusBadRequest, Err: fmt.Errorf("field %q: %w", name, err)}
+		}
+	}
+
+	return nil
+}
+
+func setField(fv reflect.Value, raw []string) error {
+
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+
+		slice := reflect.MakeSlice(fv.Type(), len(raw), len(raw))
+
+		for i, s := range raw {
+
+			if err := setValue(slice.Index(i), s); err != nil {
+
+				return err
+			}
+		}
+
+		fv.Set(slice)
+
+		return nil
+	}
+
+	return setValue(fv, raw[0])
+}
+
+func setValue(fv reflect.Value, s string) error {
+
+	if fv.Kind() == reflect.Ptr {
+
+		if fv.IsNil() {
+
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+
+		return setValue(fv.Elem(), s)
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	if fv.Type() == durationType {
+
+		d, err := time.ParseDuration(s)
+
+		if err != nil {
+
+			return err
+		}
+
+		fv.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+
+		fv.SetString(s)
+
+	case reflect.Bool:
+
+		b, err := strconv.ParseBool(s)
+
+		if err != nil {
+
+			return err
+		}
+
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		i, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+
+		if err != nil {
+
+			return err
+		}
+
+		fv.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		u, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+
+		if err != nil {
+
+			return err
+		}
+
+		fv.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+
+		if err != nil {
+
+			return err
+		}
+
+		fv.SetFloat(f)
+
+	default:
+
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
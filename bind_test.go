@@ -0,0 +1,213 @@
+package http200ok
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindTestUser struct {
+	UserID  int           `param:"UserID"`
+	Name    string        `json:"name" xml:"name" form:"name" query:"name"`
+	Tags    []string      `form:"tag" query:"tag"`
+	Age     *int          `json:"age" form:"age" query:"age"`
+	Timeout time.Duration `query:"timeout"`
+}
+
+func TestContextBind(t *testing.T) {
+
+	var user bindTestUser
+
+	app := New()
+
+	app.Handle("POST", "/users/:UserID/", Wrap(func(c *Context) error {
+
+		user = bindTestUser{}
+
+		return c.Bind(&user)
+	}))
+
+	app.Get("/users/:UserID/", Wrap(func(c *Context) error {
+
+		user = bindTestUser{}
+
+		return c.Bind(&user)
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Post(ts.URL+"/users/42/", "application/json; charset=utf-8", strings.NewReader(`{"name":"JSON","age":30}`)); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) && assert.NotNil(t, user.Age) {
+
+			assert.Equal(t, 42, user.UserID)
+			assert.Equal(t, "JSON", user.Name)
+			assert.Equal(t, 30, *user.Age)
+		}
+	}
+
+	if res, err := client.Post(ts.URL+"/users/42/", "text/xml", strings.NewReader(`<user><name>XML</name></user>`)); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, "XML", user.Name)
+		}
+	}
+
+	if res, err := client.PostForm(ts.URL+"/users/42/", url.Values{"name": {"Form"}, "tag": {"a", "b"}}); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, "Form", user.Name)
+			assert.Equal(t, []string{"a", "b"}, user.Tags)
+		}
+	}
+
+	if res, err := client.Get(ts.URL + "/users/42/?name=Query&timeout=5s&tag=c"); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, "Query", user.Name)
+			assert.Equal(t, 5*time.Second, user.Timeout)
+			assert.Equal(t, []string{"c"}, user.Tags)
+		}
+	}
+}
+
+func TestContextBindMultipart(t *testing.T) {
+
+	type Upload struct {
+		Name string                `form:"name"`
+		File *multipart.FileHeader `form:"file"`
+	}
+
+	var upload Upload
+
+	app := New()
+	app.Post("/", Wrap(func(c *Context) error {
+
+		return c.Bind(&upload)
+	}))
+
+	var (
+		body   bytes.Buffer
+		writer = multipart.NewWriter(&body)
+	)
+
+	writer.WriteField("name", "Multipart")
+
+	if part, err := writer.CreateFormFile("file", "test.txt"); assert.NoError(t, err) {
+
+		part.Write([]byte("content"))
+	}
+
+	writer.Close()
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Post(ts.URL, writer.FormDataContentType(), &body); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) && assert.NotNil(t, upload.File) {
+
+			assert.Equal(t, "Multipart", upload.Name)
+			assert.Equal(t, "test.txt", upload.File.Filename)
+		}
+	}
+}
+
+func TestContextBindErrors(t *testing.T) {
+
+	app := New()
+	app.SetMaxBodySize(16)
+	app.Post("/", Wrap(func(c *Context) error {
+
+		var user bindTestUser
+
+		return c.Bind(&user)
+	}))
+
+	app.Get("/", Wrap(func(c *Context) error {
+
+		var user bindTestUser
+
+		return c.Bind(&user)
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	cases := []struct {
+		contentType string
+		body        string
+		code        int
+	}{
+		{"application/json", `{"name":`, http.StatusBadRequest},
+		{"application/json", `{"name":"a very long name that does not fit"}`, http.StatusRequestEntityTooLarge},
+		{"text/csv", `name`, http.StatusUnsupportedMediaType},
+	}
+
+	for _, expected := range cases {
+
+		if res, err := client.Post(ts.URL, expected.contentType, strings.NewReader(expected.body)); assert.NoError(t, err) {
+
+			assert.Equal(t, expected.code, res.StatusCode)
+		}
+	}
+
+	if res, err := client.Get(ts.URL + "/?age=old"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestContextBindNonStruct(t *testing.T) {
+
+	var (
+		items []map[string]interface{}
+		doc   map[string]interface{}
+	)
+
+	app := New()
+	app.Post("/items/:ListID/", Wrap(func(c *Context) error {
+
+		return c.Bind(&items)
+	}))
+
+	app.Post("/docs/:DocID/", Wrap(func(c *Context) error {
+
+		return c.Bind(&doc)
+	}))
+
+	for path, body := range map[string]string{
+		"/items/1/": `[{"name":"a"},{"name":"b"}]`,
+		"/docs/1/":  `{"name":"b"}`,
+	} {
+
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusOK, rw.Code, path)
+	}
+
+	if assert.Len(t, items, 2) {
+
+		assert.Equal(t, "a", items[0]["name"])
+	}
+
+	assert.Equal(t, "b", doc["name"])
+}
@@ -0,0 +1,104 @@
+package http200ok
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestServerBuildOnFirstRequest(t *testing.T) {
+
+	app := New()
+	app.Get("/", func(c *Context) {})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	assert.PanicsWithValue(t, ErrServerBuilt, func() {
+
+		app.Get("/late/", func(c *Context) {})
+	})
+
+	assert.PanicsWithValue(t, ErrServerBuilt, func() {
+
+		app.Group("/admin").Post("/", func(c *Context) {})
+	})
+
+	assert.PanicsWithValue(t, ErrServerBuilt, func() {
+
+		app.Use(func(c *Context) {})
+	})
+
+	assert.PanicsWithValue(t, ErrServerBuilt, func() {
+
+		app.SetNotFoundHandler(func(c *Context) {})
+	})
+}
+
+func TestServerBuildExplicit(t *testing.T) {
+
+	app := New()
+	app.Get("/", func(c *Context) {})
+	app.Build()
+	app.Build()
+
+	assert.PanicsWithValue(t, ErrServerBuilt, func() {
+
+		app.SetErrorHandler(defaultErrorHandler)
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+// TestServerConcurrentRequests is meant to be run with -race, the first
+// requests build the server concurrently.
+func TestServerConcurrentRequests(t *testing.T) {
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		c.Set("Path", c.Request.URL.Path)
+	})
+
+	app.Get("/users/:UserID/", func(c *Context) {
+
+		c.String(http.StatusOK, c.RequestParam("UserID"))
+	})
+
+	app.Get("/panic/", func(c *Context) {
+
+		panic(NewHTTPError(http.StatusTeapot, "", nil))
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for path, code := range map[string]int{
+				"/users/42/": http.StatusOK,
+				"/panic/":    http.StatusTeapot,
+				"/missing/":  http.StatusNotFound,
+			} {
+
+				rw := httptest.NewRecorder()
+
+				app.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+				assert.Equal(t, code, rw.Code, path)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -1,22 +1,77 @@
 package http200ok
 
 import (
+	"context"
 	"github.com/julienschmidt/httprouter"
+	"math"
 	"net/http"
+	"net/url"
 	"sync"
+	"time"
 )
 
+const abortIndex = math.MaxInt / 2
+
+// Context is recycled once the handler chain returns, goroutines that
+// outlive the request must use Copy.
 type Context struct {
 	Request   *http.Request
-	Response  http.ResponseWriter
-	WebSocket webSocket
+	Response  ResponseWriter
+	WebSocket *webSocket
+	Events    *eventStream
+
+	mutex          sync.Mutex
+	writer         responseWriter
+	ctx            context.Context
+	server         *Server
+	route          string
+	requestID      string
+	transport      http.RoundTripper
+	values         map[interface{}]interface{}
+	handlers       []Handler
+	after          []func()
+	params         httprouter.Params
+	query          url.Values
+	paramErrs      []error
+	index          int
+	limited        bool
+	wsConfig       *WebSocketConfig
+	sseConfig      *SSEConfig
+	recoveryConfig *RecoveryConfig
+	err            error
+}
+
+// Copy returns a Context that stays valid after the handler returns. It
+// keeps the request, params and values but has no Response, and its
+// context.Context is still cancelled when the request ends.
+func (c *Context) Copy() *Context {
+
+	cp := &Context{
+		Request:   c.Request,
+		ctx:       c.context(),
+		server:    c.server,
+		route:     c.route,
+		requestID: c.requestID,
+		transport: c.transport,
+		params:    append(httprouter.Params(nil), c.params...),
+		index:     abortIndex,
+	}
+
+	c.mutex.Lock()
 
-	mutex    sync.Mutex
-	values   map[string]interface{}
-	handlers []Handler
-	params   httprouter.Params
-	index    int
-	stop     bool
+	if len(c.values) != 0 {
+
+		cp.values = make(map[interface{}]interface{}, len(c.values))
+
+		for k, v := range c.values {
+
+			cp.values[k] = v
+		}
+	}
+
+	c.mutex.Unlock()
+
+	return cp
 }
 
 func (c *Context) IsPost() bool {
@@ -24,6 +79,12 @@ func (c *Context) IsPost() bool {
 	return c.Request.Method == "POST"
 }
 
+// Route returns the pattern of the matched route.
+func (c *Context) Route() string {
+
+	return c.route
+}
+
 func (c *Context) RequestParam(key string) string {
 
 	return c.params.ByName(key)
@@ -31,50 +92,141 @@ func (c *Context) RequestParam(key string) string {
 
 func (c *Context) Set(key string, value interface{}) {
 
-	c.mutex.Lock()
+	c.store(key, value)
+}
 
-	c.values[key] = value
+func (c *Context) Get(key string) interface{} {
 
-	c.mutex.Unlock()
+	if v, found := c.lookup(key); found {
+
+		return v
+	}
+
+	return nil
 }
 
-func (c *Context) Get(key string) interface{} {
+func (c *Context) Deadline() (time.Time, bool) {
 
-	defer c.mutex.Unlock()
+	return c.context().Deadline()
+}
 
-	c.mutex.Lock()
+func (c *Context) Done() <-chan struct{} {
 
-	if v, found := c.values[key]; found {
+	return c.context().Done()
+}
 
-		return v
+func (c *Context) Err() error {
+
+	return c.context().Err()
+}
+
+// Value looks up keys stored with Set or SetValue first and falls back
+// to the request context.
+func (c *Context) Value(key interface{}) interface{} {
+
+	switch key.(type) {
+	case string, valueKey:
+
+		if v, found := c.lookup(key); found {
+
+			return v
+		}
 	}
 
-	return nil
+	return c.context().Value(key)
 }
 
-func (c *Context) Next() {
+func (c *Context) context() context.Context {
 
-	c.index++
+	if c.ctx != nil {
 
-	c.run()
+		return c.ctx
+	}
+
+	if c.Request != nil {
+
+		return c.Request.Context()
+	}
+
+	return context.Background()
 }
 
-func (c *Context) Stop() {
+func (c *Context) withCancel() context.CancelFunc {
+
+	ctx, cancel := context.WithCancel(c.context())
+
+	c.ctx = ctx
 
-	c.stop = true
+	return cancel
 }
 
-func (c *Context) run() {
+// Next runs the remaining handlers of the chain and returns when they are
+// done, so code after Next sees the final response. Handlers that do not
+// call Next are followed by the next one anyway.
+func (c *Context) Next() {
+
+	c.index++
 
 	for c.index < len(c.handlers) {
 
 		c.handlers[c.index](c)
 
 		c.index++
+	}
+}
 
-		if c.stop {
+func (c *Context) Error(err error) {
 
-			return
-		}
+	if c.err == nil {
+
+		c.err = err
 	}
+
+	c.Abort()
+}
+
+// Abort prevents the remaining handlers from running, the handlers that
+// already called Next still resume after it.
+func (c *Context) Abort() {
+
+	c.index = abortIndex
+}
+
+func (c *Context) AbortWithStatus(code int) {
+
+	c.Response.WriteHeader(code)
+
+	c.Abort()
+}
+
+func (c *Context) AbortWithStatusJSON(code int, v interface{}) {
+
+	c.JSON(code, v)
+
+	c.Abort()
+}
+
+// AbortWithError aborts the chain and reports err with the given status
+// through the error handler.
+func (c *Context) AbortWithError(code int, err error) {
+
+	c.Error(NewHTTPError(code, "", err))
+}
+
+func (c *Context) IsAborted() bool {
+
+	return c.index >= abortIndex
+}
+
+// onFinish registers fn to run after the error handler or the panic
+// recovery, just before the Context is released.
+func (c *Context) onFinish(fn func()) {
+
+	c.after = append(c.after, fn)
+}
+
+// Stop is an alias for Abort.
+func (c *Context) Stop() {
+
+	c.Abort()
 }
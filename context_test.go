@@ -0,0 +1,195 @@
+package http200ok
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type contextKey struct{}
+
+func TestContextValue(t *testing.T) {
+
+	app := New()
+	app.Get("/", func(c *Context) {
+
+		c.Set("CurrentUser", "bob")
+
+		var ctx context.Context = c
+
+		assert.Equal(t, "bob", ctx.Value("CurrentUser"))
+		assert.Equal(t, "from request", ctx.Value(contextKey{}))
+		assert.Nil(t, ctx.Value("Unknown"))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), contextKey{}, "from request"))
+
+	app.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestContextCancelledOnDisconnect(t *testing.T) {
+
+	cancelled := make(chan error, 1)
+
+	app := New()
+	app.Get("/", func(c *Context) {
+
+		select {
+		case <-c.Done():
+
+			cancelled <- c.Err()
+
+		case <-time.After(time.Second):
+
+			cancelled <- nil
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+
+		time.Sleep(20 * time.Millisecond)
+
+		cancel()
+	}()
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+
+	assert.Equal(t, context.Canceled, <-cancelled)
+}
+
+func TestContextCancelledOnWebSocketClose(t *testing.T) {
+
+	cancelled := make(chan error, 1)
+
+	app := New()
+	app.WebSocket("/ws/", func(c *Context) {
+
+		go c.WebSocket.Receive()
+
+		select {
+		case <-c.Done():
+
+			cancelled <- c.Err()
+
+		case <-time.After(time.Second):
+
+			cancelled <- nil
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	defer ts.Close()
+
+	dialWebSocket(t, ts, "/ws/").Close()
+
+	assert.Equal(t, context.Canceled, <-cancelled)
+}
+
+func TestTimeout(t *testing.T) {
+
+	app := New()
+	app.Get("/slow/", Timeout(10*time.Millisecond), func(c *Context) {
+
+		<-c.Done()
+	})
+
+	app.Get("/aware/", Timeout(10*time.Millisecond), Wrap(func(c *Context) error {
+
+		<-c.Request.Context().Done()
+
+		return c.Request.Context().Err()
+	}))
+
+	app.Get("/fast/", Timeout(time.Second), func(c *Context) {
+
+		_, ok := c.Deadline()
+
+		assert.True(t, ok)
+
+		c.Status(http.StatusOK)
+	})
+
+	for path, code := range map[string]int{
+		"/slow/":  http.StatusServiceUnavailable,
+		"/aware/": http.StatusGatewayTimeout,
+		"/fast/":  http.StatusOK,
+	} {
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, code, rw.Code, path)
+	}
+}
+
+func TestContextPoolReset(t *testing.T) {
+
+	var found []interface{}
+
+	app := New()
+	app.Get("/", func(c *Context) {
+
+		found = append(found, c.Get("CurrentUser"))
+
+		c.Set("CurrentUser", "bob")
+		c.Response.Before(func(rw ResponseWriter) {
+
+			rw.Header().Add("X-Before", "1")
+		})
+	})
+
+	for i := 0; i < 3; i++ {
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(t, []string{"1"}, rw.Header()["X-Before"])
+	}
+
+	assert.Equal(t, []interface{}{nil, nil, nil}, found)
+}
+
+func TestContextCopy(t *testing.T) {
+
+	copied := make(chan *Context, 1)
+
+	app := New()
+	app.Get("/users/:UserID/", func(c *Context) {
+
+		c.Set("CurrentUser", "bob")
+
+		copied <- c.Copy()
+	})
+
+	app.Get("/", func(c *Context) {
+
+		c.Set("CurrentUser", "alice")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42/", nil).WithContext(ctx))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	c := <-copied
+
+	assert.Equal(t, "bob", c.Get("CurrentUser"))
+	assert.Equal(t, "42", c.RequestParam("UserID"))
+	assert.Equal(t, "/users/:UserID/", c.Route())
+	assert.Equal(t, "/users/42/", c.Request.URL.Path)
+	assert.Nil(t, c.Response)
+	assert.NoError(t, c.Err())
+
+	cancel()
+
+	assert.Equal(t, context.Canceled, c.Err())
+}
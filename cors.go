@@ -0,0 +1,207 @@
+package http200ok
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrCORSWildcardCredentials = errors.New(`http200ok: CORS AllowOrigins "*" cannot be combined with AllowCredentials`)
+
+type CORSConfig struct {
+	// AllowOrigins may contain "*" to allow any origin or wildcard
+	// entries like "https://*.example.com". "*" together with
+	// AllowCredentials would let every site make credentialed requests,
+	// CORS panics with ErrCORSWildcardCredentials for that combination.
+	AllowOrigins        []string
+	AllowOriginPatterns []string
+	AllowOriginFunc     func(origin string) bool
+	AllowMethods        []string
+	AllowHeaders        []string
+	ExposeHeaders       []string
+	AllowCredentials    bool
+	MaxAge              time.Duration
+}
+
+func CORS(config CORSConfig) Handler {
+
+	wildcard := containsFold(config.AllowOrigins, "*")
+
+	allowOrigin, err := originMatcher(config.AllowOrigins, config.AllowOriginPatterns, config.AllowOriginFunc)
+
+	if err != nil {
+
+		panic(err)
+	}
+
+	if wildcard && config.AllowCredentials {
+
+		panic(ErrCORSWildcardCredentials)
+	}
+
+	return func(c *Context) {
+
+		header := c.Response.Header()
+		header.Add("Vary", "Origin")
+
+		origin := c.Request.Header.Get("Origin")
+
+		if origin == "" {
+
+			return
+		}
+
+		preflight := c.Request.Method == "OPTIONS" && c.Request.Header.Get("Access-Control-Request-Method") != ""
+
+		if !allowOrigin(origin) {
+
+			if preflight {
+
+				c.Response.WriteHeader(http.StatusForbidden)
+
+				c.Stop()
+			}
+
+			return
+		}
+
+		if wildcard {
+
+			header.Set("Access-Control-Allow-Origin", "*")
+
+		} else {
+
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if config.AllowCredentials {
+
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+
+			if len(config.ExposeHeaders) != 0 {
+
+				header.Set("Access-Control-Expose-Headers", strings.Join(config.ExposeHeaders, ", "))
+			}
+
+			return
+		}
+
+		methods := config.AllowMethods
+
+		if len(methods) == 0 {
+
+			methods = c.server.allowed(c.Request.URL.Path)
+		}
+
+		if !containsFold(methods, c.Request.Header.Get("Access-Control-Request-Method")) {
+
+			c.Response.WriteHeader(http.StatusForbidden)
+
+			c.Stop()
+
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+		if len(config.AllowHeaders) != 0 {
+
+			header.Set("Access-Control-Allow-Headers", strings.Join(config.AllowHeaders, ", "))
+
+		} else if requested := c.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+
+			header.Set("Access-Control-Allow-Headers", requested)
+		}
+
+		if config.MaxAge > 0 {
+
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge/time.Second)))
+		}
+
+		c.Response.WriteHeader(http.StatusNoContent)
+
+		c.Stop()
+	}
+}
+
+func originMatcher(origins, expressions []string, fn func(origin string) bool) (func(origin string) bool, error) {
+
+	var (
+		wildcard bool
+		patterns []*regexp.Regexp
+	)
+
+	for _, origin := range origins {
+
+		switch {
+		case origin == "*":
+
+			wildcard = true
+
+		case strings.Contains(origin, "*"):
+
+			expressions = append(expressions, "^"+strings.Replace(regexp.QuoteMeta(origin), `\*`, `[^/]*`, -1)+"$")
+
+		default:
+
+			expressions = append(expressions, "^"+regexp.QuoteMeta(origin)+"$")
+		}
+	}
+
+	for _, expression := range expressions {
+
+		pattern, err := regexp.Compile(expression)
+
+		if err != nil {
+
+			return nil, err
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	return func(origin string) bool {
+
+		if wildcard {
+
+			return true
+		}
+
+		for _, pattern := range patterns {
+
+			if pattern.MatchString(origin) {
+
+				return true
+			}
+		}
+
+		return fn != nil && fn(origin)
+	}, nil
+}
+
+func preflightHandler(c *Context) {
+
+	c.Response.Header().Set("Allow", strings.Join(c.server.allowed(c.Request.URL.Path), ", "))
+	c.Response.WriteHeader(http.StatusNoContent)
+}
+
+func containsFold(values []string, value string) bool {
+
+	for _, v := range values {
+
+		if strings.EqualFold(v, value) {
+
+			return true
+		}
+	}
+
+	return false
+}
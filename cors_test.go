@@ -0,0 +1,215 @@
+package http200ok
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerAutomaticOptions(t *testing.T) {
+
+	app := New()
+	app.Get("/users/:UserID/", func(_ *Context) {})
+	app.Delete("/users/:UserID/", func(_ *Context) {})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("OPTIONS", ts.URL+"/users/42/", nil); assert.NoError(t, err) {
+
+		if res, err := client.Do(req); assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			assert.Equal(t, "DELETE, GET, OPTIONS", res.Header.Get("Allow"))
+		}
+	}
+}
+
+func TestServerExplicitOptionsOverridesAutomatic(t *testing.T) {
+
+	app := New()
+	app.Get("/", func(_ *Context) {})
+	app.Options("/", func(c *Context) {
+
+		c.Response.WriteHeader(http.StatusTeapot)
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("OPTIONS", ts.URL, nil); assert.NoError(t, err) {
+
+		if res, err := client.Do(req); assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusTeapot, res.StatusCode)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+
+	var reached bool
+
+	app := New()
+	app.Use(CORS(CORSConfig{
+		AllowOrigins:     []string{"https://*.example.com"},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+
+	app.Put("/users/:UserID/", func(_ *Context) {
+
+		reached = true
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	preflight := func(origin, method string) *http.Response {
+
+		req, err := http.NewRequest("OPTIONS", ts.URL+"/users/42/", nil)
+
+		if !assert.NoError(t, err) {
+
+			t.FailNow()
+		}
+
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+
+		res, err := client.Do(req)
+
+		if !assert.NoError(t, err) {
+
+			t.FailNow()
+		}
+
+		return res
+	}
+
+	res := preflight("https://app.example.com", "PUT")
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "OPTIONS, PUT", res.Header.Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", res.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", res.Header.Get("Access-Control-Max-Age"))
+	assert.False(t, reached)
+
+	res = preflight("https://evil.com", "PUT")
+
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+
+	res = preflight("https://app.example.com", "DELETE")
+
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+
+	app := New()
+
+	api := app.Group("/api", CORS(CORSConfig{
+		AllowOrigins:  []string{"*"},
+		ExposeHeaders: []string{"X-Total-Count"},
+	}))
+
+	api.Get("/", func(_ *Context) {})
+	app.Get("/", func(_ *Context) {})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("GET", ts.URL+"/api/", nil); assert.NoError(t, err) {
+
+		req.Header.Set("Origin", "https://example.org")
+
+		if res, err := client.Do(req); assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "*", res.Header.Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "X-Total-Count", res.Header.Get("Access-Control-Expose-Headers"))
+		}
+	}
+
+	if req, err := http.NewRequest("GET", ts.URL, nil); assert.NoError(t, err) {
+
+		req.Header.Set("Origin", "https://example.org")
+
+		if res, err := client.Do(req); assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+		}
+	}
+}
+
+func TestCORSPreflightGroups(t *testing.T) {
+
+	var public bool
+
+	app := New()
+
+	pub := app.Group("/public", func(_ *Context) {
+
+		public = true
+	})
+
+	pub.Get("/a", func(_ *Context) {})
+
+	admin := app.Group("/admin", CORS(CORSConfig{
+		AllowOrigins: []string{"https://app.example.com"},
+	}))
+
+	admin.Get("/b", func(_ *Context) {})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	for path, allowOrigin := range map[string]string{"/admin/b": "https://app.example.com", "/public/a": ""} {
+
+		public = false
+
+		if req, err := http.NewRequest("OPTIONS", ts.URL+path, nil); assert.NoError(t, err) {
+
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", "GET")
+
+			if res, err := client.Do(req); assert.NoError(t, err) {
+
+				assert.Equal(t, http.StatusNoContent, res.StatusCode, path)
+				assert.Equal(t, allowOrigin, res.Header.Get("Access-Control-Allow-Origin"), path)
+				assert.Equal(t, path == "/public/a", public, path)
+			}
+		}
+	}
+}
+
+func TestCORSWildcardCredentials(t *testing.T) {
+
+	assert.PanicsWithValue(t, ErrCORSWildcardCredentials, func() {
+
+		CORS(CORSConfig{
+			AllowOrigins:     []string{"*"},
+			AllowCredentials: true,
+		})
+	})
+
+	assert.NotPanics(t, func() {
+
+		CORS(CORSConfig{
+			AllowOrigins:     []string{"https://*.example.com"},
+			AllowCredentials: true,
+		})
+	})
+}
@@ -0,0 +1,150 @@
+package http200ok
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type HandlerFunc func(c *Context) error
+
+func Wrap(handler HandlerFunc) Handler {
+
+	return func(c *Context) {
+
+		if err := handler(c); err != nil {
+
+			c.Error(err)
+		}
+	}
+}
+
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+func NewHTTPError(code int, message string, err error) *HTTPError {
+
+	return &HTTPError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func (e *HTTPError) Error() string {
+
+	if e.Err != nil {
+
+		return fmt.Sprintf("%d %s: %v", e.Code, e.message(), e.Err)
+	}
+
+	return fmt.Sprintf("%d %s", e.Code, e.message())
+}
+
+func (e *HTTPError) Unwrap() error {
+
+	return e.Err
+}
+
+func (e *HTTPError) message() string {
+
+	if e.Message != "" {
+
+		return e.Message
+	}
+
+	return http.StatusText(e.Code)
+}
+
+func errorStatus(err error) (int, string) {
+
+	var (
+		httpErr       *HTTPError
+		bindErr       *BindError
+		paramErr      *ParamError
+		validationErr ValidationErrors
+	)
+
+	switch {
+	case errors.As(err, &httpErr):
+
+		return httpErr.Code, httpErr.message()
+
+	case errors.As(err, &bindErr):
+
+		return bindErr.Code, http.StatusText(bindErr.Code)
+
+	case errors.As(err, &paramErr):
+
+		return http.StatusBadRequest, paramErr.Error()
+
+	case errors.As(err, &validationErr):
+
+		return http.StatusUnprocessableEntity, validationErr.Error()
+
+	case errors.Is(err, context.DeadlineExceeded):
+
+		return http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout)
+	}
+
+	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+}
+
+// handleError passes err to the error handler, once the response is
+// written it can no longer be changed and err is only logged.
+func (c *Context) handleError(err error) {
+
+	if c.Response.Written() {
+
+		logError(c.Request, err)
+
+		return
+	}
+
+	c.server.errorHandler(c.Response, c.Request, err)
+}
+
+func defaultErrorHandler(rw http.ResponseWriter, req *http.Request, err error) {
+
+	var validationErr ValidationErrors
+
+	if errors.As(err, &validationErr) {
+
+		rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+		rw.Header().Set("X-Content-Type-Options", "nosniff")
+		rw.WriteHeader(http.StatusUnprocessableEntity)
+
+		json.NewEncoder(rw).Encode(struct {
+			Errors ValidationErrors `json:"errors"`
+		}{validationErr})
+
+		return
+	}
+
+	code, message := errorStatus(err)
+
+	http.Error(rw, message, code)
+
+	if code >= http.StatusInternalServerError {
+
+		logError(req, err)
+	}
+}
+
+func logError(req *http.Request, err error) {
+
+	if id := RequestIDFrom(req.Context()); id != "" {
+
+		log.Printf("[%s] %s", id, panicMessage(err))
+
+		return
+	}
+
+	log.Println(panicMessage(err))
+}
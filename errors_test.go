@@ -0,0 +1,197 @@
+package http200ok
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+type testError struct {
+	Reason string
+}
+
+func (e *testError) Error() string {
+
+	return e.Reason
+}
+
+func TestServerHandlerFuncError(t *testing.T) {
+
+	var (
+		reached bool
+		handled error
+		origin  = &testError{Reason: "Boom"}
+	)
+
+	app := New()
+	app.Get("/",
+		Wrap(func(_ *Context) error {
+
+			return origin
+		}),
+		func(_ *Context) {
+
+			reached = true
+		},
+	)
+
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.False(t, reached)
+		assert.True(t, handled == origin)
+	}
+}
+
+func TestServerHandlerFuncNilError(t *testing.T) {
+
+	var reached bool
+
+	app := New()
+	app.Get("/",
+		Wrap(func(_ *Context) error {
+
+			return nil
+		}),
+		func(_ *Context) {
+
+			reached = true
+		},
+	)
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, reached)
+	}
+}
+
+func TestServerHTTPError(t *testing.T) {
+
+	app := New()
+	app.Get("/", Wrap(func(_ *Context) error {
+
+		return NewHTTPError(http.StatusNotFound, "User not found", errors.New("sql: no rows in result set"))
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Contains(t, string(body), "User not found")
+			assert.NotContains(t, string(body), "sql")
+		}
+	}
+}
+
+func TestServerPanicError(t *testing.T) {
+
+	var (
+		handled error
+		origin  = &testError{Reason: "Boom"}
+	)
+
+	app := New()
+	app.Get("/", func(_ *Context) {
+
+		panic(origin)
+	})
+
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+
+		var target *testError
+
+		if assert.True(t, errors.As(handled, &target)) {
+
+			assert.Equal(t, "Boom", target.Reason)
+		}
+	}
+}
+
+func TestErrorAfterWrite(t *testing.T) {
+
+	var (
+		buf     bytes.Buffer
+		handled bool
+	)
+
+	log.SetOutput(&buf)
+
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = true
+	})
+
+	app.Get("/error/", func(c *Context) {
+
+		c.JSON(http.StatusOK, map[string]int{"Count": 1})
+		c.Error(errors.New("late error"))
+	})
+
+	app.Get("/panic/", func(c *Context) {
+
+		c.String(http.StatusOK, "partial")
+
+		panic("late panic")
+	})
+
+	for path, body := range map[string]string{
+		"/error/": "{\"Count\":1}\n",
+		"/panic/": "partial",
+	} {
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, http.StatusOK, rw.Code, path)
+		assert.Equal(t, body, rw.Body.String(), path)
+	}
+
+	assert.False(t, handled)
+	assert.Contains(t, buf.String(), "late error")
+	assert.Contains(t, buf.String(), "panic: late panic")
+}
@@ -3,11 +3,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"github.com/ops-console/http200ok"
-	"github.com/ops-console/http200ok/render"
+	"github.com/postgres-ci/http200ok"
+	"github.com/postgres-ci/http200ok/render"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"time"
 )
 
@@ -23,6 +26,8 @@ func (u *User) IsAuth() bool {
 	return u.UserID != 0
 }
 
+var CurrentUser = http200ok.NewKey[*User]("CurrentUser")
+
 var tpl = `
 <!DOCTYPE html>
 <html>
@@ -50,22 +55,26 @@ func main() {
 	render.FromString("index.html", tpl)
 
 	app := http200ok.New()
+	app.SetWebSocketConfig(http200ok.WebSocketConfig{
+		PingInterval: 30 * time.Second,
+		PongTimeout:  10 * time.Second,
+	})
 
 	app.Use(func(c *http200ok.Context) {
 
-		c.Set("CurrentUser", &User{UserID: 1})
+		http200ok.SetValue(c, CurrentUser, &User{UserID: 1})
 
 		fmt.Println("I'm a global middleware", c.Request.RequestURI)
 	})
 
 	app.Get("/", func(c *http200ok.Context) {
 
-		render.HTML(c.Response, "index.html", nil)
+		c.HTML(http.StatusOK, "index.html", nil)
 	})
 
 	app.WebSocket("/ws/", func(c *http200ok.Context) {
 		/*
-			user, ok := c.Get("CurrentUser").(*User)
+			user, ok := http200ok.Value(c, CurrentUser)
 
 			if !ok || !user.IsAuth() {
 
@@ -80,7 +89,7 @@ func main() {
 
 	}, func(c *http200ok.Context) {
 
-		user, ok := c.Get("CurrentUser").(*User)
+		user, ok := http200ok.Value(c, CurrentUser)
 
 		if ok && user.IsAuth() {
 
@@ -100,11 +109,34 @@ func main() {
 
 			i++
 
-			<-time.After(time.Second)
+			select {
+			case <-time.After(time.Second):
+			case <-c.WebSocket.Done():
+
+				return
+			}
 		}
 
 	})
 
+	app.SSE("/events/", func(c *http200ok.Context) {
+
+		for i := 0; ; i++ {
+
+			if err := c.Events.SendJSON("message", fmt.Sprint(i), Message{Message: fmt.Sprintf("Hello %d", i)}); err != nil {
+
+				return
+			}
+
+			select {
+			case <-time.After(time.Second):
+			case <-c.Events.Done():
+
+				return
+			}
+		}
+	})
+
 	app.Get("/panic/", func(c *http200ok.Context) {
 
 		panic("AAA")
@@ -115,10 +147,28 @@ func main() {
 		http.Error(rw, fmt.Sprintf("Panic: %s", err.Error()), http.StatusInternalServerError)
 	})
 
-	app.SetNotFoundHandler(func(rw http.ResponseWriter, req *http.Request) {
+	app.SetNotFoundHandler(func(c *http200ok.Context) {
 
-		http.Error(rw, fmt.Sprintf("%s not found", req.RequestURI), http.StatusNotFound)
+		http.Error(c.Response, fmt.Sprintf("%s not found", c.Request.RequestURI), http.StatusNotFound)
 	})
 
-	log.Fatal(http.ListenAndServe(":9009", app))
+	go func() {
+
+		signals := make(chan os.Signal, 1)
+
+		signal.Notify(signals, os.Interrupt)
+
+		<-signals
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		defer cancel()
+
+		app.Shutdown(ctx)
+	}()
+
+	if err := app.Run(":9009"); err != nil {
+
+		log.Fatal(err)
+	}
 }
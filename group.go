@@ -0,0 +1,75 @@
+package http200ok
+
+type Group struct {
+	server   *Server
+	prefix   string
+	handlers []Handler
+}
+
+func (s *Server) Group(prefix string, middleware ...Handler) *Group {
+
+	return &Group{
+		server:   s,
+		prefix:   prefix,
+		handlers: middleware,
+	}
+}
+
+func (g *Group) Group(prefix string, middleware ...Handler) *Group {
+
+	return &Group{
+		server:   g.server,
+		prefix:   g.prefix + prefix,
+		handlers: append(g.handlers[:len(g.handlers):len(g.handlers)], middleware...),
+	}
+}
+
+func (g *Group) Delete(pattern string, handlers ...Handler) {
+
+	g.server.add("DELETE", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Get(pattern string, handlers ...Handler) {
+
+	g.server.add("GET", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Head(pattern string, handlers ...Handler) {
+
+	g.server.add("HEAD", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Options(pattern string, handlers ...Handler) {
+
+	g.server.add("OPTIONS", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Patch(pattern string, handlers ...Handler) {
+
+	g.server.add("PATCH", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Post(pattern string, handlers ...Handler) {
+
+	g.server.add("POST", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Put(pattern string, handlers ...Handler) {
+
+	g.server.add("PUT", g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) Handle(method, pattern string, handlers ...Handler) {
+
+	g.server.add(method, g.prefix+pattern, g.handlers, handlers)
+}
+
+func (g *Group) WebSocket(pattern string, handlers ...Handler) {
+
+	g.server.add("GET", g.prefix+pattern, g.handlers, wsHandlers(handlers))
+}
+
+func (g *Group) SSE(pattern string, handlers ...Handler) {
+
+	g.server.add("GET", g.prefix+pattern, g.handlers, sseHandlers(handlers))
+}
@@ -0,0 +1,278 @@
+package http200ok
+
+import (
+	"encoding/json"
+	"errors"
+	"golang.org/x/net/websocket"
+	"sync"
+)
+
+const defaultHubQueueSize = 16
+
+var ErrNoWebSocket = errors.New("http200ok: context has no websocket")
+
+type SlowConsumerPolicy uint8
+
+const (
+	DropMessage SlowConsumerPolicy = iota
+	Disconnect
+)
+
+type HubConfig struct {
+	QueueSize int
+	Policy    SlowConsumerPolicy
+}
+
+type Hub struct {
+	config  HubConfig
+	mutex   sync.RWMutex
+	clients map[*webSocket]*hubClient
+	rooms   map[string]map[*hubClient]struct{}
+}
+
+type hubClient struct {
+	socket *webSocket
+	queue  chan interface{}
+	rooms  map[string]struct{}
+	done   chan struct{}
+}
+
+func NewHub(config HubConfig) *Hub {
+
+	if config.QueueSize <= 0 {
+
+		config.QueueSize = defaultHubQueueSize
+	}
+
+	return &Hub{
+		config:  config,
+		clients: make(map[*webSocket]*hubClient),
+		rooms:   make(map[string]map[*hubClient]struct{}),
+	}
+}
+
+// Join registers the WebSocket of c in the given rooms, the connection is
+// removed from the hub as soon as it is closed.
+func (h *Hub) Join(c *Context, rooms ...string) error {
+
+	if c.WebSocket == nil {
+
+		return ErrNoWebSocket
+	}
+
+	h.mutex.Lock()
+
+	defer h.mutex.Unlock()
+
+	client, found := h.clients[c.WebSocket]
+
+	if !found {
+
+		client = &hubClient{
+			socket: c.WebSocket,
+			queue:  make(chan interface{}, h.config.QueueSize),
+			rooms:  make(map[string]struct{}),
+			done:   make(chan struct{}),
+		}
+
+		h.clients[c.WebSocket] = client
+
+		go h.write(client)
+	}
+
+	for _, room := range rooms {
+
+		if _, found := h.rooms[room]; !found {
+
+			h.rooms[room] = make(map[*hubClient]struct{})
+		}
+
+		h.rooms[room][client] = struct{}{}
+		client.rooms[room] = struct{}{}
+	}
+
+	return nil
+}
+
+func (h *Hub) Leave(c *Context, rooms ...string) {
+
+	h.mutex.Lock()
+
+	defer h.mutex.Unlock()
+
+	client, found := h.clients[c.WebSocket]
+
+	if !found {
+
+		return
+	}
+
+	if len(rooms) == 0 {
+
+		h.remove(client)
+
+		return
+	}
+
+	for _, room := range rooms {
+
+		h.leave(client, room)
+	}
+}
+
+func (h *Hub) Len(room string) int {
+
+	h.mutex.RLock()
+
+	defer h.mutex.RUnlock()
+
+	return len(h.rooms[room])
+}
+
+func (h *Hub) Broadcast(room string, msgType int, data []byte) {
+
+	h.mutex.Lock()
+
+	defer h.mutex.Unlock()
+
+	message := hubPayload(msgType, data)
+
+	for client := range h.rooms[room] {
+
+		h.enqueue(client, message)
+	}
+}
+
+func (h *Hub) BroadcastJSON(room string, v interface{}) error {
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+
+		return err
+	}
+
+	h.Broadcast(room, TextMessage, data)
+
+	return nil
+}
+
+func (h *Hub) BroadcastAll(msgType int, data []byte) {
+
+	h.mutex.Lock()
+
+	defer h.mutex.Unlock()
+
+	message := hubPayload(msgType, data)
+
+	for _, client := range h.clients {
+
+		h.enqueue(client, message)
+	}
+}
+
+func (h *Hub) BroadcastAllJSON(v interface{}) error {
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+
+		return err
+	}
+
+	h.BroadcastAll(TextMessage, data)
+
+	return nil
+}
+
+func (h *Hub) enqueue(client *hubClient, message interface{}) {
+
+	select {
+	case client.queue <- message:
+	default:
+
+		if h.config.Policy == Disconnect {
+
+			h.remove(client)
+
+			go client.socket.Close(ClosePolicyViolation, "slow consumer")
+		}
+	}
+}
+
+func (h *Hub) write(client *hubClient) {
+
+	for {
+
+		select {
+		case message := <-client.queue:
+
+			if client.socket.isClosed() || client.socket.send(websocket.Message, message) != nil {
+
+				h.mutex.Lock()
+
+				h.remove(client)
+
+				h.mutex.Unlock()
+
+				return
+			}
+
+		case <-client.socket.Done():
+
+			h.mutex.Lock()
+
+			h.remove(client)
+
+			h.mutex.Unlock()
+
+			return
+
+		case <-client.done:
+
+			return
+		}
+	}
+}
+
+func (h *Hub) remove(client *hubClient) {
+
+	if _, found := h.clients[client.socket]; !found {
+
+		return
+	}
+
+	for room := range client.rooms {
+
+		h.leave(client, room)
+	}
+
+	delete(h.clients, client.socket)
+
+	close(client.done)
+}
+
+func (h *Hub) leave(client *hubClient, room string) {
+
+	delete(client.rooms, room)
+
+	if members, found := h.rooms[room]; found {
+
+		delete(members, client)
+
+		if len(members) == 0 {
+
+			delete(h.rooms, room)
+		}
+	}
+}
+
+func hubPayload(msgType int, data []byte) interface{} {
+
+	if msgType == BinaryMessage {
+
+		return data
+	}
+
+	return string(data)
+}
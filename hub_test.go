@@ -0,0 +1,115 @@
+package http200ok
+
+import (
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHubBroadcast(t *testing.T) {
+
+	type Event struct {
+		Event string
+	}
+
+	var (
+		hub    = NewHub(HubConfig{})
+		joined = make(chan struct{}, 3)
+	)
+
+	app := New()
+	app.WebSocket("/ws/:Room", func(c *Context) {
+
+		if assert.NoError(t, hub.Join(c, c.RequestParam("Room"))) {
+
+			joined <- struct{}{}
+
+			c.WebSocket.OnMessage(func(int, []byte) error {
+
+				return nil
+			})
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	chat1 := dialWebSocket(t, ts, "/ws/chat")
+	chat2 := dialWebSocket(t, ts, "/ws/chat")
+	other := dialWebSocket(t, ts, "/ws/other")
+
+	for i := 0; i < 3; i++ {
+
+		<-joined
+	}
+
+	assert.Equal(t, 2, hub.Len("chat"))
+	assert.Equal(t, 1, hub.Len("other"))
+
+	if assert.NoError(t, hub.BroadcastJSON("chat", Event{Event: "chat"})) {
+
+		for _, ws := range []*websocket.Conn{chat1, chat2} {
+
+			var event Event
+
+			if assert.NoError(t, websocket.JSON.Receive(ws, &event)) {
+
+				assert.Equal(t, "chat", event.Event)
+			}
+		}
+	}
+
+	hub.BroadcastAll(BinaryMessage, []byte{42})
+
+	for _, ws := range []*websocket.Conn{chat1, chat2, other} {
+
+		var data []byte
+
+		if assert.NoError(t, websocket.Message.Receive(ws, &data)) {
+
+			assert.Equal(t, []byte{42}, data)
+		}
+	}
+
+	chat1.Close()
+
+	for deadline := time.Now().Add(5 * time.Second); hub.Len("chat") != 1 && time.Now().Before(deadline); {
+
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, 1, hub.Len("chat"))
+
+	chat2.Close()
+	other.Close()
+}
+
+func TestHubDropMessage(t *testing.T) {
+
+	hub := NewHub(HubConfig{QueueSize: 1, Policy: DropMessage})
+
+	client := &hubClient{
+		socket: &webSocket{done: make(chan struct{})},
+		queue:  make(chan interface{}, 1),
+		rooms:  map[string]struct{}{"room": {}},
+		done:   make(chan struct{}),
+	}
+
+	hub.clients[client.socket] = client
+	hub.rooms["room"] = map[*hubClient]struct{}{client: {}}
+
+	hub.Broadcast("room", TextMessage, []byte("first"))
+	hub.Broadcast("room", TextMessage, []byte("second"))
+
+	if assert.Len(t, client.queue, 1) {
+
+		assert.Equal(t, "first", <-client.queue)
+		assert.Equal(t, 1, hub.Len("room"))
+	}
+}
+
+func TestHubJoinWithoutWebSocket(t *testing.T) {
+
+	assert.Equal(t, ErrNoWebSocket, NewHub(HubConfig{}).Join(&Context{}, "room"))
+}
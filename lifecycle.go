@@ -0,0 +1,279 @@
+package http200ok
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+var ErrServerRunning = errors.New("http200ok: server is already running")
+
+func (s *Server) OnStart(hook func() error) {
+
+	s.lifecycle.Lock()
+
+	s.onStart = append(s.onStart, hook)
+
+	s.lifecycle.Unlock()
+}
+
+func (s *Server) OnShutdown(hook func(ctx context.Context) error) {
+
+	s.lifecycle.Lock()
+
+	s.onShutdown = append(s.onShutdown, hook)
+
+	s.lifecycle.Unlock()
+}
+
+func (s *Server) Run(addr string) error {
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+
+		return err
+	}
+
+	return s.serve(listener, "", "")
+}
+
+func (s *Server) RunTLS(addr, certFile, keyFile string) error {
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+
+		return err
+	}
+
+	return s.serve(listener, certFile, keyFile)
+}
+
+func (s *Server) RunListener(listener net.Listener) error {
+
+	return s.serve(listener, "", "")
+}
+
+// Shutdown stops accepting connections, sends a close frame to every open
+// WebSocket and waits for in-flight handlers until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+
+	s.lifecycle.Lock()
+
+	srv, done := s.httpServer, s.done
+
+	if srv == nil || s.closing {
+
+		s.lifecycle.Unlock()
+
+		return nil
+	}
+
+	s.closing = true
+
+	var (
+		hooks   = s.onShutdown
+		sockets = make([]*webSocket, 0, len(s.sockets))
+		streams = make([]*eventStream, 0, len(s.streams))
+	)
+
+	for socket := range s.sockets {
+
+		sockets = append(sockets, socket)
+	}
+
+	for stream := range s.streams {
+
+		streams = append(streams, stream)
+	}
+
+	s.lifecycle.Unlock()
+
+	for _, stream := range streams {
+
+		stream.close()
+	}
+
+	for _, socket := range sockets {
+
+		go socket.Close(CloseGoingAway, "")
+	}
+
+	errs := []error{srv.Shutdown(ctx)}
+
+	finished := make(chan struct{})
+
+	go func() {
+
+		s.socketsWG.Wait()
+
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+
+		for _, socket := range sockets {
+
+			socket.ws.Close()
+		}
+
+		errs = append(errs, ctx.Err())
+	}
+
+	for _, hook := range hooks {
+
+		errs = append(errs, hook(ctx))
+	}
+
+	s.lifecycle.Lock()
+
+	s.httpServer = nil
+
+	s.lifecycle.Unlock()
+
+	close(done)
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) serve(listener net.Listener, certFile, keyFile string) error {
+
+	s.lifecycle.Lock()
+
+	if s.httpServer != nil || s.starting {
+
+		s.lifecycle.Unlock()
+
+		listener.Close()
+
+		return ErrServerRunning
+	}
+
+	s.starting = true
+
+	hooks := s.onStart
+
+	s.lifecycle.Unlock()
+
+	// hooks run without the lock, they may register OnShutdown hooks.
+	for _, hook := range hooks {
+
+		if err := hook(); err != nil {
+
+			s.lifecycle.Lock()
+
+			s.starting = false
+
+			s.lifecycle.Unlock()
+
+			listener.Close()
+
+			return err
+		}
+	}
+
+	s.Build()
+
+	s.lifecycle.Lock()
+
+	srv := &http.Server{Handler: s}
+
+	s.httpServer, s.done, s.closing, s.starting = srv, make(chan struct{}), false, false
+
+	done := s.done
+
+	s.lifecycle.Unlock()
+
+	var err error
+
+	if certFile != "" || keyFile != "" {
+
+		err = srv.ServeTLS(listener, certFile, keyFile)
+
+	} else {
+
+		err = srv.Serve(listener)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+
+		<-done
+
+		return nil
+	}
+
+	s.lifecycle.Lock()
+
+	s.httpServer = nil
+
+	s.lifecycle.Unlock()
+
+	return err
+}
+
+func (s *Server) trackSocket(socket *webSocket) bool {
+
+	s.lifecycle.Lock()
+
+	defer s.lifecycle.Unlock()
+
+	if s.closing {
+
+		return false
+	}
+
+	if s.sockets == nil {
+
+		s.sockets = make(map[*webSocket]struct{})
+	}
+
+	s.sockets[socket] = struct{}{}
+	s.socketsWG.Add(1)
+
+	return true
+}
+
+func (s *Server) untrackSocket(socket *webSocket) {
+
+	s.lifecycle.Lock()
+
+	delete(s.sockets, socket)
+
+	s.lifecycle.Unlock()
+
+	s.socketsWG.Done()
+}
+
+func (s *Server) trackStream(stream *eventStream) bool {
+
+	s.lifecycle.Lock()
+
+	defer s.lifecycle.Unlock()
+
+	if s.closing {
+
+		return false
+	}
+
+	if s.streams == nil {
+
+		s.streams = make(map[*eventStream]struct{})
+	}
+
+	s.streams[stream] = struct{}{}
+
+	return true
+}
+
+func (s *Server) untrackStream(stream *eventStream) {
+
+	s.lifecycle.Lock()
+
+	delete(s.streams, stream)
+
+	s.lifecycle.Unlock()
+}
@@ -0,0 +1,258 @@
+package http200ok
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerShutdownWaitsForInFlight(t *testing.T) {
+
+	var (
+		started  = make(chan struct{})
+		stopped  = make(chan error, 1)
+		hooks    []string
+		listener net.Listener
+		err      error
+	)
+
+	if listener, err = net.Listen("tcp", "127.0.0.1:0"); !assert.NoError(t, err) {
+
+		return
+	}
+
+	app := New()
+	app.OnStart(func() error {
+
+		hooks = append(hooks, "start")
+
+		return nil
+	})
+
+	app.OnShutdown(func(context.Context) error {
+
+		hooks = append(hooks, "shutdown")
+
+		return nil
+	})
+
+	app.Get("/", func(c *Context) {
+
+		close(started)
+
+		time.Sleep(100 * time.Millisecond)
+
+		fmt.Fprint(c.Response, "Done")
+	})
+
+	go func() {
+
+		stopped <- app.RunListener(listener)
+	}()
+
+	responses := make(chan *http.Response, 1)
+
+	go func() {
+
+		res, err := http.Get("http://" + listener.Addr().String())
+
+		if assert.NoError(t, err) {
+
+			responses <- res
+		}
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer cancel()
+
+	if assert.NoError(t, app.Shutdown(ctx)) {
+
+		res := <-responses
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Equal(t, "Done", string(body))
+		}
+
+		assert.NoError(t, <-stopped)
+		assert.Equal(t, []string{"start", "shutdown"}, hooks)
+	}
+
+	_, err = http.Get("http://" + listener.Addr().String())
+
+	assert.Error(t, err)
+}
+
+func TestServerShutdownClosesWebSockets(t *testing.T) {
+
+	var (
+		connected = make(chan struct{})
+		finished  = make(chan struct{})
+		stopped   = make(chan error, 1)
+		listener  net.Listener
+		err       error
+	)
+
+	if listener, err = net.Listen("tcp", "127.0.0.1:0"); !assert.NoError(t, err) {
+
+		return
+	}
+
+	app := New()
+	app.WebSocket("/ws/", func(c *Context) {
+
+		close(connected)
+
+		var message string
+
+		for websocket.Message.Receive(c.WebSocket.Conn(), &message) == nil {
+		}
+
+		close(finished)
+	})
+
+	go func() {
+
+		stopped <- app.RunListener(listener)
+	}()
+
+	addr := listener.Addr().String()
+
+	ws, err := websocket.Dial("ws://"+addr+"/ws/", "", "http://"+addr)
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	<-connected
+
+	go func() {
+
+		var message string
+
+		if err := websocket.Message.Receive(ws, &message); errors.Is(err, io.EOF) {
+
+			ws.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer cancel()
+
+	if assert.NoError(t, app.Shutdown(ctx)) {
+
+		<-finished
+
+		assert.NoError(t, <-stopped)
+	}
+}
+
+func TestServerRunTwice(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	app := New()
+
+	stopped := make(chan error, 1)
+
+	go func() {
+
+		stopped <- app.RunListener(listener)
+	}()
+
+	for {
+
+		if _, err := http.Get("http://" + listener.Addr().String()); err == nil {
+
+			break
+		}
+	}
+
+	second, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if assert.NoError(t, err) {
+
+		assert.Equal(t, ErrServerRunning, app.RunListener(second))
+	}
+
+	assert.NoError(t, app.Shutdown(context.Background()))
+	assert.NoError(t, <-stopped)
+}
+
+func TestServerOnStartRegistersShutdownHook(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	var shutdown bool
+
+	app := New()
+	app.Get("/", func(c *Context) {})
+	app.OnStart(func() error {
+
+		app.OnShutdown(func(ctx context.Context) error {
+
+			shutdown = true
+
+			return nil
+		})
+
+		return nil
+	})
+
+	stopped := make(chan error, 1)
+
+	go func() {
+
+		stopped <- app.RunListener(listener)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+
+		res, err := http.Get("http://" + listener.Addr().String())
+
+		if err == nil {
+
+			res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			break
+		}
+
+		if time.Now().After(deadline) {
+
+			t.Fatal("server did not start")
+		}
+	}
+
+	assert.NoError(t, app.Shutdown(context.Background()))
+	assert.NoError(t, <-stopped)
+	assert.True(t, shutdown)
+}
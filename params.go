@@ -0,0 +1,206 @@
+package http200ok
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errMissingParam = errors.New("missing value")
+
+type ParamError struct {
+	Name   string
+	Source string
+	Value  string
+	Err    error
+}
+
+func (e *ParamError) Error() string {
+
+	return fmt.Sprintf("invalid %s parameter %q: %v", e.Source, e.Name, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+
+	return e.Err
+}
+
+func (c *Context) Params() httprouter.Params {
+
+	return c.params
+}
+
+func (c *Context) ParamInt(key string) (int, error) {
+
+	v, err := c.paramInt(key, strconv.IntSize)
+
+	return int(v), err
+}
+
+func (c *Context) ParamInt64(key string) (int64, error) {
+
+	return c.paramInt(key, 64)
+}
+
+func (c *Context) ParamUUID(key string) (string, error) {
+
+	value, found := c.param(key)
+
+	if !found {
+
+		return "", c.paramError(key, "path", value, errMissingParam)
+	}
+
+	if !uuidRegexp.MatchString(value) {
+
+		return "", c.paramError(key, "path", value, errors.New("not a valid UUID"))
+	}
+
+	return strings.ToLower(value), nil
+}
+
+func (c *Context) Query(key string) string {
+
+	return c.queryValues().Get(key)
+}
+
+func (c *Context) QueryStrings(key string) []string {
+
+	return c.queryValues()[key]
+}
+
+func (c *Context) QueryInt(key string, def int) (int, error) {
+
+	value := c.Query(key)
+
+	if value == "" {
+
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(value)
+
+	if err != nil {
+
+		return def, c.paramError(key, "query", value, numError(err))
+	}
+
+	return v, nil
+}
+
+func (c *Context) QueryBool(key string, def bool) (bool, error) {
+
+	value := c.Query(key)
+
+	if value == "" {
+
+		return def, nil
+	}
+
+	v, err := strconv.ParseBool(value)
+
+	if err != nil {
+
+		return def, c.paramError(key, "query", value, numError(err))
+	}
+
+	return v, nil
+}
+
+func (c *Context) QueryDuration(key string, def time.Duration) (time.Duration, error) {
+
+	value := c.Query(key)
+
+	if value == "" {
+
+		return def, nil
+	}
+
+	v, err := time.ParseDuration(value)
+
+	if err != nil {
+
+		return def, c.paramError(key, "query", value, errors.New("not a valid duration"))
+	}
+
+	return v, nil
+}
+
+// ParamErrors returns every error reported by the typed accessors so
+// far, which lets a handler read all parameters and check once.
+func (c *Context) ParamErrors() error {
+
+	return errors.Join(c.paramErrs...)
+}
+
+func (c *Context) param(key string) (string, bool) {
+
+	for _, param := range c.params {
+
+		if param.Key == key {
+
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (c *Context) paramInt(key string, bitSize int) (int64, error) {
+
+	value, found := c.param(key)
+
+	if !found {
+
+		return 0, c.paramError(key, "path", value, errMissingParam)
+	}
+
+	v, err := strconv.ParseInt(value, 10, bitSize)
+
+	if err != nil {
+
+		return 0, c.paramError(key, "path", value, numError(err))
+	}
+
+	return v, nil
+}
+
+func (c *Context) paramError(name, source, value string, err error) error {
+
+	paramErr := &ParamError{
+		Name:   name,
+		Source: source,
+		Value:  value,
+		Err:    err,
+	}
+
+	c.paramErrs = append(c.paramErrs, paramErr)
+
+	return paramErr
+}
+
+func (c *Context) queryValues() url.Values {
+
+	if c.query == nil {
+
+		c.query = c.Request.URL.Query()
+	}
+
+	return c.query
+}
+
+func numError(err error) error {
+
+	var numErr *strconv.NumError
+
+	if errors.As(err, &numErr) {
+
+		return numErr.Err
+	}
+
+	return err
+}
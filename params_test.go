@@ -0,0 +1,108 @@
+package http200ok
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextTypedParams(t *testing.T) {
+
+	var (
+		userID  int
+		orderID int64
+		uuid    string
+		page    int
+		active  bool
+		timeout time.Duration
+		tags    []string
+		keys    []string
+	)
+
+	app := New()
+	app.Get("/users/:UserID/orders/:OrderID/:UUID", Wrap(func(c *Context) error {
+
+		userID, _ = c.ParamInt("UserID")
+		orderID, _ = c.ParamInt64("OrderID")
+		uuid, _ = c.ParamUUID("UUID")
+		page, _ = c.QueryInt("page", 1)
+		active, _ = c.QueryBool("active", false)
+		timeout, _ = c.QueryDuration("timeout", time.Second)
+		tags = c.QueryStrings("tag")
+
+		for _, param := range c.Params() {
+
+			keys = append(keys, param.Key)
+		}
+
+		return c.ParamErrors()
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/users/42/orders/9000000000/C9F2A7E4-5B6D-4C1E-9A3F-2D8B7E6F1A0C?active=true&tag=a&tag=b"); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, 42, userID)
+			assert.Equal(t, int64(9000000000), orderID)
+			assert.Equal(t, "c9f2a7e4-5b6d-4c1e-9a3f-2d8b7e6f1a0c", uuid)
+			assert.Equal(t, 1, page)
+			assert.True(t, active)
+			assert.Equal(t, time.Second, timeout)
+			assert.Equal(t, []string{"a", "b"}, tags)
+			assert.Equal(t, []string{"UserID", "OrderID", "UUID"}, keys)
+		}
+	}
+}
+
+func TestContextTypedParamsError(t *testing.T) {
+
+	app := New()
+	app.Get("/users/:UserID/", Wrap(func(c *Context) error {
+
+		if _, err := c.ParamInt("UserID"); err != nil {
+
+			return err
+		}
+
+		return nil
+	}))
+
+	app.Get("/search/", Wrap(func(c *Context) error {
+
+		c.QueryInt("page", 1)
+		c.QueryDuration("timeout", time.Second)
+
+		return c.ParamErrors()
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/users/abc/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Contains(t, string(body), `invalid path parameter "UserID"`)
+		}
+	}
+
+	if res, err := client.Get(ts.URL + "/search/?page=two&timeout=soon"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Contains(t, string(body), `invalid query parameter "page"`)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package http200ok
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is reported to the error handler when a handler panics.
+type PanicError struct {
+	Value     interface{}
+	Stack     []byte
+	Route     string
+	RequestID string
+}
+
+func (e *PanicError) Error() string {
+
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so a panic with an
+// *HTTPError keeps its status.
+func (e *PanicError) Unwrap() error {
+
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+type RecoveryConfig struct {
+	// Repanic propagates the panic after the socket is closed and the
+	// handler has run, meant for tests.
+	Repanic bool
+	// Handler is called instead of the error handler.
+	Handler func(c *Context, err *PanicError)
+}
+
+func (s *Server) SetRecoveryConfig(config RecoveryConfig) {
+	s.mutable()
+	s.recoveryConfig = config
+}
+
+func RecoveryOptions(config RecoveryConfig) Handler {
+
+	return func(c *Context) {
+
+		c.recoveryConfig = &config
+	}
+}
+
+func (c *Context) panicError(rcv interface{}) *PanicError {
+
+	if err, ok := rcv.(*PanicError); ok {
+
+		return err
+	}
+
+	return &PanicError{
+		Value:     rcv,
+		Stack:     debug.Stack(),
+		Route:     c.route,
+		RequestID: c.requestID,
+	}
+}
+
+// recover reports the panic and returns it when the policy asks to
+// propagate it. A panic that follows an error already reported through
+// c.Error, such as asserting the nil returned by MustGet, is only logged
+// and the reported error goes to the error handler.
+func (c *Context) recover(rcv interface{}) *PanicError {
+
+	err := c.panicError(rcv)
+
+	config := c.server.recoveryConfig
+
+	if c.recoveryConfig != nil {
+
+		config = *c.recoveryConfig
+	}
+
+	switch {
+	case c.err != nil:
+
+		logError(c.Request, err)
+
+		c.handleError(c.err)
+
+	case config.Handler != nil:
+
+		config.Handler(c, err)
+
+	default:
+
+		c.handleError(err)
+	}
+
+	if config.Repanic {
+
+		return err
+	}
+
+	return nil
+}
+
+func panicMessage(err error) string {
+
+	var panicErr *PanicError
+
+	if errors.As(err, &panicErr) {
+
+		return fmt.Sprintf("%v\n%s", err, panicErr.Stack)
+	}
+
+	return err.Error()
+}
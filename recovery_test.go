@@ -0,0 +1,147 @@
+package http200ok
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoveryPanicError(t *testing.T) {
+
+	var handled error
+
+	app := New()
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		code, _ := errorStatus(err)
+
+		rw.WriteHeader(code)
+	})
+
+	app.Use(RequestID(RequestIDConfig{}))
+	app.Get("/users/:UserID/", func(c *Context) {
+
+		panic("AAA")
+	})
+
+	app.Get("/forbidden/", func(c *Context) {
+
+		panic(NewHTTPError(http.StatusForbidden, "", nil))
+	})
+
+	req := httptest.NewRequest("GET", "/users/42/", nil)
+	req.Header.Set("X-Request-ID", "client-id-1")
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+
+	var panicErr *PanicError
+
+	if assert.True(t, errors.As(handled, &panicErr)) {
+
+		assert.Equal(t, "AAA", panicErr.Value)
+		assert.Equal(t, "/users/:UserID/", panicErr.Route)
+		assert.Equal(t, "client-id-1", panicErr.RequestID)
+		assert.Contains(t, string(panicErr.Stack), "recovery_test.go")
+		assert.Equal(t, "panic: AAA", panicErr.Error())
+	}
+
+	rw = httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/forbidden/", nil))
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestRecoveryRepanic(t *testing.T) {
+
+	var recovered *PanicError
+
+	app := New()
+	app.SetRecoveryConfig(RecoveryConfig{Repanic: true})
+	app.Get("/", func(c *Context) {
+
+		panic("AAA")
+	})
+
+	app.Get("/report/", RecoveryOptions(RecoveryConfig{
+		Handler: func(c *Context, err *PanicError) {
+
+			recovered = err
+
+			c.Response.WriteHeader(http.StatusServiceUnavailable)
+		},
+	}), func(c *Context) {
+
+		panic("BBB")
+	})
+
+	assert.Panics(t, func() {
+
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+
+	rw := httptest.NewRecorder()
+
+	assert.NotPanics(t, func() {
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", "/report/", nil))
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	if assert.NotNil(t, recovered) {
+
+		assert.Equal(t, "BBB", recovered.Value)
+	}
+}
+
+func TestRecoveryWebSocket(t *testing.T) {
+
+	handled := make(chan *PanicError, 1)
+
+	app := New()
+	app.SetRecoveryConfig(RecoveryConfig{
+		Handler: func(c *Context, err *PanicError) {
+
+			handled <- err
+		},
+	})
+
+	app.WebSocket("/ws/", func(c *Context) {
+
+		panic("AAA")
+	})
+
+	ts := httptest.NewServer(app)
+
+	defer ts.Close()
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	if frame, err := ws.NewFrameReader(); assert.NoError(t, err) {
+
+		assert.Equal(t, byte(websocket.CloseFrame), frame.PayloadType())
+
+		if payload, err := ioutil.ReadAll(frame); assert.NoError(t, err) && assert.True(t, len(payload) >= 2) {
+
+			assert.Equal(t, CloseInternalServerErr, int(binary.BigEndian.Uint16(payload)))
+		}
+	}
+
+	if panicErr := <-handled; assert.NotNil(t, panicErr) {
+
+		assert.Equal(t, "/ws/", panicErr.Route)
+		assert.Contains(t, string(panicErr.Stack), "recovery_test.go")
+	}
+}
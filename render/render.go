@@ -0,0 +1,448 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+var Default = New()
+
+type source func() (map[string]string, error)
+
+type Registry struct {
+	mutex       sync.RWMutex
+	funcs       template.FuncMap
+	layout      string
+	development bool
+	partials    []source
+	pages       []source
+	templates   map[string]*template.Template
+	broken      map[string]error
+}
+
+func New() *Registry {
+
+	return &Registry{
+		funcs: make(template.FuncMap),
+	}
+}
+
+// Funcs must be called before the templates using them are registered,
+// templates are parsed on registration.
+func (r *Registry) Funcs(funcs template.FuncMap) {
+
+	r.mutex.Lock()
+
+	for name, fn := range funcs {
+
+		r.funcs[name] = fn
+	}
+
+	r.templates = nil
+
+	r.mutex.Unlock()
+}
+
+// SetLayout makes every page render through the named partial, pages
+// provide the blocks the layout refers to.
+func (r *Registry) SetLayout(name string) {
+
+	r.mutex.Lock()
+
+	r.layout = name
+
+	r.mutex.Unlock()
+}
+
+// SetDevelopment enables reloading of all sources on every render.
+func (r *Registry) SetDevelopment(development bool) {
+
+	r.mutex.Lock()
+
+	r.development = development
+	r.templates = nil
+
+	r.mutex.Unlock()
+}
+
+func (r *Registry) FromString(name, text string) error {
+
+	return r.addPage(fromString(name, text))
+}
+
+func (r *Registry) FromFile(name, filename string) error {
+
+	return r.addPage(fromFile(name, filename))
+}
+
+func (r *Registry) FromGlob(pattern string) error {
+
+	return r.addPage(fromGlob(pattern))
+}
+
+func (r *Registry) FromFS(fsys fs.FS, patterns ...string) error {
+
+	return r.addPage(fromFS(fsys, patterns))
+}
+
+func (r *Registry) PartialFromString(name, text string) error {
+
+	return r.addPartial(fromString(name, text))
+}
+
+func (r *Registry) PartialFromFile(name, filename string) error {
+
+	return r.addPartial(fromFile(name, filename))
+}
+
+func (r *Registry) PartialsFromGlob(pattern string) error {
+
+	return r.addPartial(fromGlob(pattern))
+}
+
+func (r *Registry) PartialsFromFS(fsys fs.FS, patterns ...string) error {
+
+	return r.addPartial(fromFS(fsys, patterns))
+}
+
+func (r *Registry) Load() error {
+
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
+	return r.build()
+}
+
+func (r *Registry) Render(w io.Writer, name string, data interface{}) error {
+
+	t, layout, err := r.lookup(name)
+
+	if err != nil {
+
+		return err
+	}
+
+	if layout != "" && t.Lookup(layout) != nil {
+
+		return t.ExecuteTemplate(w, layout, data)
+	}
+
+	return t.ExecuteTemplate(w, name, data)
+}
+
+func (r *Registry) HTML(rw http.ResponseWriter, name string, data interface{}) error {
+
+	var buf bytes.Buffer
+
+	if err := r.Render(&buf, name, data); err != nil {
+
+		return err
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	_, err := buf.WriteTo(rw)
+
+	return err
+}
+
+func (r *Registry) lookup(name string) (*template.Template, string, error) {
+
+	r.mutex.RLock()
+
+	if !r.development && r.templates != nil {
+
+		t, layout, err := r.find(name)
+
+		r.mutex.RUnlock()
+
+		return t, layout, err
+	}
+
+	r.mutex.RUnlock()
+
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
+	if r.development || r.templates == nil {
+
+		if err := r.build(); err != nil {
+
+			return nil, "", err
+		}
+	}
+
+	return r.find(name)
+}
+
+func (r *Registry) find(name string) (*template.Template, string, error) {
+
+	if err, found := r.broken[name]; found {
+
+		return nil, "", err
+	}
+
+	t, found := r.templates[name]
+
+	if !found {
+
+		return nil, "", fmt.Errorf("render: template %q is not registered", name)
+	}
+
+	return t, r.layout, nil
+}
+
+func (r *Registry) addPage(src source) error {
+
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
+	if err := r.check(src); err != nil {
+
+		return err
+	}
+
+	r.pages = append(r.pages, src)
+	r.templates = nil
+
+	return nil
+}
+
+func (r *Registry) addPartial(src source) error {
+
+	r.mutex.Lock()
+
+	defer r.mutex.Unlock()
+
+	if err := r.check(src); err != nil {
+
+		return err
+	}
+
+	r.partials = append(r.partials, src)
+	r.templates = nil
+
+	return nil
+}
+
+// check reads and parses the source, so a syntax error is returned on
+// registration instead of on the first render.
+func (r *Registry) check(src source) error {
+
+	texts, err := src()
+
+	if err != nil {
+
+		return err
+	}
+
+	for name, text := range texts {
+
+		if _, err := template.New(name).Funcs(r.funcs).Parse(text); err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) build() error {
+
+	base := template.New("").Funcs(r.funcs)
+
+	for _, src := range r.partials {
+
+		texts, err := src()
+
+		if err != nil {
+
+			return err
+		}
+
+		for name, text := range texts {
+
+			if _, err := base.New(name).Parse(text); err != nil {
+
+				return err
+			}
+		}
+	}
+
+	var (
+		templates = make(map[string]*template.Template)
+		broken    = make(map[string]error)
+	)
+
+	// a page that fails to parse, after a reload in development mode,
+	// only breaks its own render.
+	for _, src := range r.pages {
+
+		texts, err := src()
+
+		if err != nil {
+
+			return err
+		}
+
+		for name, text := range texts {
+
+			t, err := base.Clone()
+
+			if err == nil {
+
+				_, err = t.New(name).Parse(text)
+			}
+
+			if err != nil {
+
+				broken[name] = err
+
+				continue
+			}
+
+			delete(broken, name)
+
+			templates[name] = t
+		}
+	}
+
+	r.templates, r.broken = templates, broken
+
+	return nil
+}
+
+func fromString(name, text string) source {
+
+	return func() (map[string]string, error) {
+
+		return map[string]string{name: text}, nil
+	}
+}
+
+func fromFile(name, filename string) source {
+
+	return func() (map[string]string, error) {
+
+		b, err := os.ReadFile(filename)
+
+		if err != nil {
+
+			return nil, err
+		}
+
+		return map[string]string{name: string(b)}, nil
+	}
+}
+
+func fromGlob(pattern string) source {
+
+	return func() (map[string]string, error) {
+
+		filenames, err := filepath.Glob(pattern)
+
+		if err != nil {
+
+			return nil, err
+		}
+
+		if len(filenames) == 0 {
+
+			return nil, fmt.Errorf("render: pattern matches no files: %#q", pattern)
+		}
+
+		texts := make(map[string]string, len(filenames))
+
+		for _, filename := range filenames {
+
+			b, err := os.ReadFile(filename)
+
+			if err != nil {
+
+				return nil, err
+			}
+
+			texts[filepath.Base(filename)] = string(b)
+		}
+
+		return texts, nil
+	}
+}
+
+func fromFS(fsys fs.FS, patterns []string) source {
+
+	return func() (map[string]string, error) {
+
+		texts := make(map[string]string)
+
+		for _, pattern := range patterns {
+
+			filenames, err := fs.Glob(fsys, pattern)
+
+			if err != nil {
+
+				return nil, err
+			}
+
+			if len(filenames) == 0 {
+
+				return nil, fmt.Errorf("render: pattern matches no files: %#q", pattern)
+			}
+
+			for _, filename := range filenames {
+
+				b, err := fs.ReadFile(fsys, filename)
+
+				if err != nil {
+
+					return nil, err
+				}
+
+				texts[path.Base(filename)] = string(b)
+			}
+		}
+
+		return texts, nil
+	}
+}
+
+func FromString(name, text string) error {
+
+	return Default.FromString(name, text)
+}
+
+func FromFile(name, filename string) error {
+
+	return Default.FromFile(name, filename)
+}
+
+func FromGlob(pattern string) error {
+
+	return Default.FromGlob(pattern)
+}
+
+func FromFS(fsys fs.FS, patterns ...string) error {
+
+	return Default.FromFS(fsys, patterns...)
+}
+
+func Render(w io.Writer, name string, data interface{}) error {
+
+	return Default.Render(w, name, data)
+}
+
+func HTML(rw http.ResponseWriter, name string, data interface{}) error {
+
+	return Default.HTML(rw, name, data)
+}
@@ -0,0 +1,200 @@
+package render
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"html/template"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegistryFromString(t *testing.T) {
+
+	r := New()
+
+	if assert.NoError(t, r.FromString("index.html", "<p>{{ .Message }}</p>")) {
+
+		var buf bytes.Buffer
+
+		if assert.NoError(t, r.Render(&buf, "index.html", map[string]string{"Message": "<b>"})) {
+
+			assert.Equal(t, "<p>&lt;b&gt;</p>", buf.String())
+		}
+	}
+}
+
+func TestRegistryNotRegistered(t *testing.T) {
+
+	r := New()
+
+	assert.Error(t, r.Render(&bytes.Buffer{}, "404.html", nil))
+}
+
+func TestRegistryLayoutAndPartials(t *testing.T) {
+
+	r := New()
+	r.Funcs(template.FuncMap{
+		"upper": strings.ToUpper,
+	})
+
+	r.SetLayout("layout.html")
+
+	assert.NoError(t, r.PartialFromString("layout.html", `<main>{{ template "content" . }}</main>{{ template "footer" }}`))
+	assert.NoError(t, r.PartialFromString("footer.html", `{{ define "footer" }}<footer></footer>{{ end }}`))
+	assert.NoError(t, r.FromString("a.html", `{{ define "content" }}A {{ upper . }}{{ end }}`))
+	assert.NoError(t, r.FromString("b.html", `{{ define "content" }}B {{ . }}{{ end }}`))
+
+	var buf bytes.Buffer
+
+	if assert.NoError(t, r.Render(&buf, "a.html", "page")) {
+
+		assert.Equal(t, "<main>A PAGE</main><footer></footer>", buf.String())
+	}
+
+	buf.Reset()
+
+	if assert.NoError(t, r.Render(&buf, "b.html", "page")) {
+
+		assert.Equal(t, "<main>B page</main><footer></footer>", buf.String())
+	}
+}
+
+func TestRegistryFromFS(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"templates/index.html": {Data: []byte("Index")},
+		"templates/about.html": {Data: []byte("About")},
+	}
+
+	r := New()
+
+	if assert.NoError(t, r.FromFS(fsys, "templates/*.html")) {
+
+		for name, expected := range map[string]string{"index.html": "Index", "about.html": "About"} {
+
+			var buf bytes.Buffer
+
+			if assert.NoError(t, r.Render(&buf, name, nil)) {
+
+				assert.Equal(t, expected, buf.String())
+			}
+		}
+	}
+
+	assert.Error(t, r.FromFS(fsys, "missing/*.html"))
+}
+
+func TestRegistryDevelopmentReload(t *testing.T) {
+
+	dir := t.TempDir()
+
+	filename := filepath.Join(dir, "index.html")
+
+	if !assert.NoError(t, os.WriteFile(filename, []byte("v1"), 0644)) {
+
+		return
+	}
+
+	r := New()
+	r.SetDevelopment(true)
+
+	if assert.NoError(t, r.FromGlob(filepath.Join(dir, "*.html"))) {
+
+		var buf bytes.Buffer
+
+		if assert.NoError(t, r.Render(&buf, "index.html", nil)) {
+
+			assert.Equal(t, "v1", buf.String())
+		}
+
+		if assert.NoError(t, os.WriteFile(filename, []byte("v2"), 0644)) {
+
+			buf.Reset()
+
+			if assert.NoError(t, r.Render(&buf, "index.html", nil)) {
+
+				assert.Equal(t, "v2", buf.String())
+			}
+		}
+	}
+}
+
+func TestRegistryHTML(t *testing.T) {
+
+	r := New()
+
+	if assert.NoError(t, r.FromString("index.html", "Hello")) {
+
+		rw := httptest.NewRecorder()
+
+		if assert.NoError(t, r.HTML(rw, "index.html", nil)) {
+
+			assert.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+			assert.Equal(t, "Hello", rw.Body.String())
+		}
+	}
+
+	assert.NoError(t, r.FromString("broken.html", "{{ .Missing.Field }}"))
+
+	rw := httptest.NewRecorder()
+
+	assert.Error(t, r.HTML(rw, "broken.html", 42))
+	assert.Equal(t, 0, rw.Body.Len())
+}
+
+func TestRegistryParseError(t *testing.T) {
+
+	r := New()
+
+	assert.Error(t, r.FromString("broken.html", "{{ if }}"))
+	assert.Error(t, r.PartialFromString("broken.html", "{{ end }}"))
+	assert.Error(t, r.FromFS(fstest.MapFS{"broken.html": {Data: []byte("{{ .Missing")}}, "*.html"))
+	assert.Error(t, r.FromString("upper.html", "{{ upper . }}"))
+
+	if assert.NoError(t, r.FromString("index.html", "Index")) {
+
+		var buf bytes.Buffer
+
+		if assert.NoError(t, r.Render(&buf, "index.html", nil)) {
+
+			assert.Equal(t, "Index", buf.String())
+		}
+	}
+}
+
+func TestRegistryDevelopmentParseError(t *testing.T) {
+
+	dir := t.TempDir()
+
+	for name, text := range map[string]string{"index.html": "Index", "about.html": "About"} {
+
+		if !assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0644)) {
+
+			return
+		}
+	}
+
+	r := New()
+	r.SetDevelopment(true)
+
+	if !assert.NoError(t, r.FromGlob(filepath.Join(dir, "*.html"))) {
+
+		return
+	}
+
+	if assert.NoError(t, os.WriteFile(filepath.Join(dir, "about.html"), []byte("{{ if }}"), 0644)) {
+
+		var buf bytes.Buffer
+
+		if assert.NoError(t, r.Render(&buf, "index.html", nil)) {
+
+			assert.Equal(t, "Index", buf.String())
+		}
+
+		assert.Error(t, r.Render(&buf, "about.html", nil))
+	}
+}
@@ -0,0 +1,137 @@
+package http200ok
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const maxRequestIDLength = 128
+
+type RequestIDConfig struct {
+	// Header defaults to X-Request-ID.
+	Header string
+	// Generator defaults to 16 random bytes in hex.
+	Generator func() string
+	// IgnoreIncoming always generates a new ID instead of accepting the
+	// one sent by the client.
+	IgnoreIncoming bool
+	// Transport is used by the client returned from Context.HTTPClient,
+	// it defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+type requestIDKey struct{}
+
+// RequestID accepts the request ID sent by the client or generates a new
+// one, stores it on the Context and echoes it in the response.
+func RequestID(config RequestIDConfig) Handler {
+
+	if config.Header == "" {
+
+		config.Header = "X-Request-ID"
+	}
+
+	if config.Generator == nil {
+
+		config.Generator = generateRequestID
+	}
+
+	if config.Transport == nil {
+
+		config.Transport = http.DefaultTransport
+	}
+
+	return func(c *Context) {
+
+		id := c.Request.Header.Get(config.Header)
+
+		if config.IgnoreIncoming || !validRequestID(id) {
+
+			id = config.Generator()
+		}
+
+		ctx := context.WithValue(c.context(), requestIDKey{}, id)
+
+		c.ctx, c.Request = ctx, c.Request.WithContext(ctx)
+		c.requestID = id
+		c.transport = &requestIDTransport{
+			base:   config.Transport,
+			header: config.Header,
+			id:     id,
+		}
+
+		c.Response.Header().Set(config.Header, id)
+	}
+}
+
+// RequestIDFrom returns the request ID stored in ctx by the RequestID
+// middleware, for code that only has the *http.Request.
+func RequestIDFrom(ctx context.Context) string {
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+func (c *Context) RequestID() string {
+
+	return c.requestID
+}
+
+// HTTPClient returns a client for outbound calls that forwards the
+// request ID.
+func (c *Context) HTTPClient() *http.Client {
+
+	if c.transport == nil {
+
+		return http.DefaultClient
+	}
+
+	return &http.Client{Transport: c.transport}
+}
+
+type requestIDTransport struct {
+	base   http.RoundTripper
+	header string
+	id     string
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Header.Get(t.header) == "" {
+
+		req = req.Clone(req.Context())
+		req.Header.Set(t.header, t.id)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+func generateRequestID() string {
+
+	var id [16]byte
+
+	rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
+
+func validRequestID(id string) bool {
+
+	if id == "" || len(id) > maxRequestIDLength {
+
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+
+		if id[i] < 0x21 || id[i] > 0x7e {
+
+			return false
+		}
+	}
+
+	return true
+}
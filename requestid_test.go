@@ -0,0 +1,124 @@
+package http200ok
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+
+	var id string
+
+	app := New()
+	app.Use(RequestID(RequestIDConfig{}))
+	app.Get("/", func(c *Context) {
+
+		id = c.RequestID()
+
+		assert.Equal(t, id, RequestIDFrom(c.Request.Context()))
+		assert.Equal(t, id, RequestIDFrom(c))
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	if assert.Len(t, id, 32) {
+
+		assert.Equal(t, id, rw.Header().Get("X-Request-ID"))
+	}
+
+	for incoming, accepted := range map[string]bool{
+		"client-id-1": true,
+		"with space":  false,
+		string(bytes.Repeat([]byte{'a'}, maxRequestIDLength+1)): false,
+	} {
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Request-ID", incoming)
+
+		app.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, accepted, id == incoming, incoming)
+	}
+}
+
+func TestRequestIDConfig(t *testing.T) {
+
+	app := New()
+	app.Use(RequestID(RequestIDConfig{
+		Header:         "X-Trace-ID",
+		Generator:      func() string { return "generated" },
+		IgnoreIncoming: true,
+	}))
+
+	app.Get("/", func(c *Context) {})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Trace-ID", "incoming")
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, req)
+
+	assert.Equal(t, "generated", rw.Header().Get("X-Trace-ID"))
+}
+
+func TestRequestIDHTTPClient(t *testing.T) {
+
+	var forwarded string
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+
+		forwarded = req.Header.Get("X-Request-ID")
+	}))
+
+	defer upstream.Close()
+
+	app := New()
+	app.Use(RequestID(RequestIDConfig{}))
+	app.Get("/", func(c *Context) {
+
+		res, err := c.HTTPClient().Get(upstream.URL)
+
+		if assert.NoError(t, err) {
+
+			res.Body.Close()
+		}
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "client-id-1")
+
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "client-id-1", forwarded)
+}
+
+func TestRequestIDErrorLog(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	log.SetOutput(&buf)
+
+	defer log.SetOutput(os.Stderr)
+
+	app := New()
+	app.Use(RequestID(RequestIDConfig{}))
+	app.Get("/", Wrap(func(c *Context) error {
+
+		return NewHTTPError(http.StatusInternalServerError, "", nil)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "client-id-1")
+
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), "[client-id-1]")
+}
@@ -0,0 +1,113 @@
+package http200ok
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+func (c *Context) Status(code int) {
+
+	c.Response.WriteHeader(code)
+}
+
+func (c *Context) JSON(code int, v interface{}) {
+
+	var buf bytes.Buffer
+
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+
+		c.Error(err)
+
+		return
+	}
+
+	c.Data(code, "application/json; charset=utf-8", buf.Bytes())
+}
+
+func (c *Context) XML(code int, v interface{}) {
+
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+
+		c.Error(err)
+
+		return
+	}
+
+	c.Data(code, "application/xml; charset=utf-8", buf.Bytes())
+}
+
+func (c *Context) HTML(code int, name string, data interface{}) {
+
+	var buf bytes.Buffer
+
+	if err := c.server.renderer.Render(&buf, name, data); err != nil {
+
+		c.Error(err)
+
+		return
+	}
+
+	c.Data(code, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func (c *Context) String(code int, format string, args ...interface{}) {
+
+	if len(args) != 0 {
+
+		format = fmt.Sprintf(format, args...)
+	}
+
+	c.Data(code, "text/plain; charset=utf-8", []byte(format))
+}
+
+func (c *Context) Data(code int, contentType string, data []byte) {
+
+	header := c.Response.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	c.Response.WriteHeader(code)
+
+	if c.Request.Method == "HEAD" || !bodyAllowed(code) {
+
+		return
+	}
+
+	c.Response.Write(data)
+}
+
+func (c *Context) Redirect(code int, url string) {
+
+	if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+
+		c.Error(fmt.Errorf("http200ok: invalid redirect status code %d", code))
+
+		return
+	}
+
+	http.Redirect(c.Response, c.Request, url, code)
+}
+
+func (c *Context) NoContent(code int) {
+
+	c.Response.WriteHeader(code)
+}
+
+func bodyAllowed(code int) bool {
+
+	switch {
+	case code >= 100 && code <= 199:
+		return false
+	case code == http.StatusNoContent, code == http.StatusNotModified:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package http200ok
+
+import (
+	"github.com/postgres-ci/http200ok/render"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextResponseHelpers(t *testing.T) {
+
+	type Message struct {
+		Message string `json:"message" xml:"message"`
+	}
+
+	app := New()
+	app.Get("/json/", func(c *Context) {
+
+		c.JSON(http.StatusCreated, Message{Message: "JSON"})
+	})
+
+	app.Get("/xml/", func(c *Context) {
+
+		c.XML(http.StatusOK, Message{Message: "XML"})
+	})
+
+	app.Get("/string/", func(c *Context) {
+
+		c.String(http.StatusOK, "Hello %s", "String")
+	})
+
+	app.Get("/data/", func(c *Context) {
+
+		c.Data(http.StatusOK, "application/octet-stream", []byte{1, 2, 3})
+	})
+
+	app.Get("/no-content/", func(c *Context) {
+
+		c.NoContent(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		url         string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/json/", http.StatusCreated, "application/json; charset=utf-8", "{\"message\":\"JSON\"}\n"},
+		{"/xml/", http.StatusOK, "application/xml; charset=utf-8", "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Message><message>XML</message></Message>"},
+		{"/string/", http.StatusOK, "text/plain; charset=utf-8", "Hello String"},
+		{"/data/", http.StatusOK, "application/octet-stream", "\x01\x02\x03"},
+		{"/no-content/", http.StatusNoContent, "", ""},
+	}
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	for _, expected := range cases {
+
+		if res, err := client.Get(ts.URL + expected.url); assert.NoError(t, err) {
+
+			assert.Equal(t, expected.code, res.StatusCode)
+			assert.Equal(t, expected.contentType, res.Header.Get("Content-Type"))
+
+			if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+				assert.Equal(t, expected.body, string(body))
+			}
+		}
+	}
+}
+
+func TestContextRedirect(t *testing.T) {
+
+	app := New()
+	app.Get("/old/", func(c *Context) {
+
+		c.Redirect(http.StatusMovedPermanently, "/new/")
+	})
+
+	app.Get("/invalid/", func(c *Context) {
+
+		c.Redirect(http.StatusOK, "/new/")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+
+			return http.ErrUseLastResponse
+		},
+	}
+
+	if res, err := client.Get(ts.URL + "/old/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+		assert.Equal(t, "/new/", res.Header.Get("Location"))
+	}
+
+	if res, err := client.Get(ts.URL + "/invalid/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	}
+}
+
+func TestContextJSONEncodingError(t *testing.T) {
+
+	var handled error
+
+	app := New()
+	app.Get("/", func(c *Context) {
+
+		c.JSON(http.StatusOK, math.Inf(1))
+	})
+
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Error(t, handled)
+	}
+}
+
+func TestContextHTML(t *testing.T) {
+
+	var handled error
+
+	renderer := render.New()
+	renderer.FromString("index.html", "<h1>{{ . }}</h1>")
+
+	app := New()
+	app.SetRenderer(renderer)
+	app.Get("/", func(c *Context) {
+
+		c.HTML(http.StatusOK, "index.html", "Hello")
+	})
+
+	app.Get("/missing/", func(c *Context) {
+
+		c.HTML(http.StatusOK, "missing.html", nil)
+	})
+
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Equal(t, "<h1>Hello</h1>", string(body))
+		}
+	}
+
+	if res, err := client.Get(ts.URL + "/missing/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Error(t, handled)
+	}
+}
@@ -0,0 +1,169 @@
+package http200ok
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+var ErrHijackUnsupported = errors.New("http200ok: response does not support hijacking")
+
+// ResponseWriter wraps the http.ResponseWriter handed to the server and
+// tracks what the handlers wrote to it.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	http.CloseNotifier
+	http.Pusher
+
+	// Status returns the written status code, 200 until the header is
+	// written.
+	Status() int
+	// Size returns the number of body bytes written.
+	Size() int
+	Written() bool
+	// Before registers a function called just before the header is
+	// written, functions run in reverse order of registration.
+	Before(func(ResponseWriter))
+	// Unwrap returns the original http.ResponseWriter.
+	Unwrap() http.ResponseWriter
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	size     int
+	written  bool
+	hijacked bool
+	before   []func(ResponseWriter)
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+
+	if w.written {
+
+		return
+	}
+
+	w.status = code
+
+	for i := len(w.before) - 1; i >= 0; i-- {
+
+		w.before[i](w)
+	}
+
+	w.written = true
+
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+
+	if w.hijacked {
+
+		return 0, http.ErrHijacked
+	}
+
+	if !w.written {
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(data)
+
+	w.size += n
+
+	return n, err
+}
+
+func (w *responseWriter) Status() int {
+
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+
+	return w.written
+}
+
+func (w *responseWriter) Before(fn func(ResponseWriter)) {
+
+	w.before = append(w.before, fn)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) Flush() {
+
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+
+	if !ok {
+
+		return
+	}
+
+	if !w.written {
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	flusher.Flush()
+}
+
+// Hijack hands the connection over to the caller, the response counts as
+// written with 101 Switching Protocols and further writes are dropped.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+
+	if !ok {
+
+		return nil, nil, ErrHijackUnsupported
+	}
+
+	conn, rw, err := hijacker.Hijack()
+
+	if err == nil {
+
+		if !w.written {
+
+			w.status, w.written = http.StatusSwitchingProtocols, true
+		}
+
+		w.hijacked = true
+	}
+
+	return conn, rw, err
+}
+
+// CloseNotify returns a channel that never fires if the original
+// http.ResponseWriter does not support it.
+func (w *responseWriter) CloseNotify() <-chan bool {
+
+	if notifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+
+		return pusher.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
@@ -0,0 +1,106 @@
+package http200ok
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseWriterTracking(t *testing.T) {
+
+	var (
+		status  int
+		size    int
+		written bool
+	)
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		assert.False(t, c.Response.Written())
+		assert.Equal(t, http.StatusOK, c.Response.Status())
+
+		c.Next()
+
+		status, size, written = c.Response.Status(), c.Response.Size(), c.Response.Written()
+	})
+
+	app.Get("/", func(c *Context) {
+
+		c.String(http.StatusAccepted, "Hello")
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, rw.Code)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, 5, size)
+	assert.True(t, written)
+}
+
+func TestResponseWriterBefore(t *testing.T) {
+
+	var order []string
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		c.Response.Before(func(rw ResponseWriter) {
+
+			order = append(order, "first")
+
+			rw.Header().Set("X-Status", http.StatusText(rw.Status()))
+		})
+
+		c.Response.Before(func(rw ResponseWriter) {
+
+			order = append(order, "second")
+		})
+	})
+
+	app.Get("/", func(c *Context) {
+
+		c.Response.WriteHeader(http.StatusNotFound)
+		c.Response.WriteHeader(http.StatusOK)
+	})
+
+	app.Get("/empty/", func(c *Context) {})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, "Not Found", rw.Header().Get("X-Status"))
+	assert.Equal(t, []string{"second", "first"}, order)
+
+	rw = httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/empty/", nil))
+
+	assert.Equal(t, "OK", rw.Header().Get("X-Status"))
+}
+
+func TestResponseWriterInterfaces(t *testing.T) {
+
+	recorder := httptest.NewRecorder()
+
+	rw := &responseWriter{ResponseWriter: recorder, status: http.StatusOK}
+
+	assert.Equal(t, recorder, rw.Unwrap())
+
+	_, _, err := rw.Hijack()
+
+	assert.Equal(t, ErrHijackUnsupported, err)
+	assert.Equal(t, http.ErrNotSupported, rw.Push("/style.css", nil))
+	assert.NotNil(t, rw.CloseNotify())
+
+	rw.Flush()
+
+	assert.True(t, rw.Written())
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
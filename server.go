@@ -1,144 +1,427 @@
 package http200ok
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/julienschmidt/httprouter"
-	"log"
+	"github.com/postgres-ci/http200ok/render"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
+var ErrServerBuilt = errors.New("http200ok: server configuration cannot change after Build")
+
 type Handler func(c *Context)
 type ErrorHandler func(http.ResponseWriter, *http.Request, error)
 
-type method uint8
-
-const (
-	delete method = iota + 1
-	get
-	head
-	post
-	put
-)
+type route struct {
+	method     string
+	pattern    string
+	middleware []Handler
+	handlers   []Handler
+}
 
 func New() *Server {
-	return &Server{
-		router: httprouter.New(),
-		errorHandler: func(rw http.ResponseWriter, _ *http.Request, err error) {
-
-			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-
-			log.Println(err)
-		},
-		notFoundHandler: func(rw http.ResponseWriter, req *http.Request) {
-
-			http.NotFound(rw, req)
+	s := &Server{
+		router:       httprouter.New(),
+		renderer:     render.Default,
+		maxBodySize:  defaultMaxBodySize,
+		errorHandler: defaultErrorHandler,
+		allowHeader:  true,
+		notFoundHandlers: []Handler{func(c *Context) {
+
+			http.NotFound(c.Response, c.Request)
+		}},
+		methodNotAllowedHandlers: []Handler{func(c *Context) {
+
+			http.Error(c.Response, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}},
+	}
 
-		},
-		methodNotAllowedHandler: func(rw http.ResponseWriter, _ *http.Request) {
+	s.pool.New = func() interface{} {
 
-			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		},
+		return &Context{}
 	}
+
+	return s
 }
 
 type Server struct {
-	router   *httprouter.Router
-	handlers []Handler
-
-	errorHandler            ErrorHandler
-	notFoundHandler         http.HandlerFunc
-	methodNotAllowedHandler http.HandlerFunc
+	router     *httprouter.Router
+	preflights *httprouter.Router
+	routes     []*route
+	handlers   []Handler
+	renderer   *render.Registry
+
+	maxBodySize     int64
+	webSocketConfig WebSocketConfig
+	sseConfig       SSEConfig
+	recoveryConfig  RecoveryConfig
+
+	lifecycle  sync.Mutex
+	httpServer *http.Server
+	done       chan struct{}
+	closing    bool
+	starting   bool
+	sockets    map[*webSocket]struct{}
+	streams    map[*eventStream]struct{}
+	socketsWG  sync.WaitGroup
+	onStart    []func() error
+	onShutdown []func(ctx context.Context) error
+
+	errorHandler             ErrorHandler
+	allowHeader              bool
+	notFoundHandlers         []Handler
+	methodNotAllowedHandlers []Handler
+
+	build sync.Once
+	built atomic.Bool
+	pool  sync.Pool
 }
 
 func (s *Server) SetErrorHandler(handler ErrorHandler) {
+	s.mutable()
 	s.errorHandler = handler
 }
 
-func (s *Server) SetNotFoundHandler(handler http.HandlerFunc) {
-	s.notFoundHandler = handler
+func (s *Server) SetRenderer(renderer *render.Registry) {
+	s.mutable()
+	s.renderer = renderer
+}
+
+func (s *Server) SetMaxBodySize(size int64) {
+	s.mutable()
+	s.maxBodySize = size
+}
+
+// SetNotFoundHandler sets the handlers for requests that match no route,
+// they run after the global middleware.
+func (s *Server) SetNotFoundHandler(handlers ...Handler) {
+	s.mutable()
+	s.notFoundHandlers = handlers
+}
+
+// SetMethodNotAllowedHandler sets the handlers for requests whose path
+// matches a route registered for other methods, they run after the global
+// middleware.
+func (s *Server) SetMethodNotAllowedHandler(handlers ...Handler) {
+	s.mutable()
+	s.methodNotAllowedHandlers = handlers
 }
 
-func (s *Server) SetMethodNotAllowedHandler(handler http.HandlerFunc) {
-	s.methodNotAllowedHandler = handler
+// SetAllowHeader controls whether 405 responses list the methods of the
+// path in the Allow header, it is enabled by default.
+func (s *Server) SetAllowHeader(enabled bool) {
+	s.mutable()
+	s.allowHeader = enabled
 }
 
+// Use appends global middleware, it applies to the routes registered
+// after the call.
 func (s *Server) Use(handler ...Handler) {
 
+	s.mutable()
+
 	s.handlers = append(s.handlers, handler...)
 }
 
 func (s *Server) Delete(pattern string, handlers ...Handler) {
 
-	s.add(delete, pattern, handlers)
+	s.add("DELETE", pattern, nil, handlers)
 }
 
 func (s *Server) Get(pattern string, handlers ...Handler) {
 
-	s.add(get, pattern, handlers)
+	s.add("GET", pattern, nil, handlers)
 }
 
 func (s *Server) Head(pattern string, handlers ...Handler) {
 
-	s.add(head, pattern, handlers)
+	s.add("HEAD", pattern, nil, handlers)
+}
+
+func (s *Server) Options(pattern string, handlers ...Handler) {
+
+	s.add("OPTIONS", pattern, nil, handlers)
+}
+
+func (s *Server) Patch(pattern string, handlers ...Handler) {
+
+	s.add("PATCH", pattern, nil, handlers)
 }
 
 func (s *Server) Post(pattern string, handlers ...Handler) {
 
-	s.add(post, pattern, handlers)
+	s.add("POST", pattern, nil, handlers)
 }
 
 func (s *Server) Put(pattern string, handlers ...Handler) {
 
-	s.add(put, pattern, handlers)
+	s.add("PUT", pattern, nil, handlers)
+}
+
+func (s *Server) Handle(method, pattern string, handlers ...Handler) {
+
+	s.add(method, pattern, nil, handlers)
 }
 
 func (s *Server) WebSocket(pattern string, handlers ...Handler) {
 
-	i := len(handlers) - 1
+	s.add("GET", pattern, nil, wsHandlers(handlers))
+}
+
+func (s *Server) SSE(pattern string, handlers ...Handler) {
 
-	s.add(get, pattern, append(handlers[:i], append([]Handler{wsMiddleware()}, handlers[i:]...)...))
+	s.add("GET", pattern, nil, sseHandlers(handlers))
 }
 
-func (s *Server) add(method method, pattern string, handlers []Handler) {
+func (s *Server) add(method, pattern string, middleware, handlers []Handler) {
+
+	s.mutable()
+
+	middleware = combine(s.handlers, middleware)
+
+	s.handle(&route{
+		method:     method,
+		pattern:    pattern,
+		middleware: middleware,
+		handlers:   combine(middleware, handlers),
+	})
+}
 
-	handler := func(rw http.ResponseWriter, req *http.Request, params httprouter.Params) {
+func (s *Server) handle(r *route) {
 
-		c := Context{
-			mutex:    sync.Mutex{},
-			Response: rw,
-			Request:  req,
-			params:   params,
-			handlers: append(s.handlers, handlers...),
-			values:   make(map[string]interface{}),
+	s.routes = append(s.routes, r)
+
+	s.router.Handle(r.method, r.pattern, func(rw http.ResponseWriter, req *http.Request, params httprouter.Params) {
+
+		s.dispatch(rw, req, params, r)
+	})
+}
+
+func (s *Server) dispatch(rw http.ResponseWriter, req *http.Request, params httprouter.Params, r *route) {
+
+	c := s.acquire(rw, req, params, r)
+
+	defer func() {
+
+		var repanic *PanicError
+
+		if rcv := recover(); rcv != nil {
+
+			repanic = c.recover(rcv)
+		}
+
+		for i := len(c.after) - 1; i >= 0; i-- {
+
+			c.after[i]()
+		}
+
+		s.release(c)
+
+		if repanic != nil {
+
+			panic(repanic)
 		}
+	}()
 
-		c.run()
+	c.Next()
+
+	if c.err != nil {
+
+		c.handleError(c.err)
 	}
 
-	switch method {
-	case delete:
-		s.router.DELETE(pattern, handler)
-	case get:
-		s.router.GET(pattern, handler)
-	case head:
-		s.router.HEAD(pattern, handler)
-	case post:
-		s.router.POST(pattern, handler)
-	case put:
-		s.router.PUT(pattern, handler)
+	if !c.Response.Written() {
+
+		c.Response.WriteHeader(http.StatusOK)
 	}
 }
 
-func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+// combine copies handlers into a new slice, so routes never share the
+// backing array of the global or group middleware.
+func combine(handlers ...[]Handler) []Handler {
 
-	s.router.PanicHandler = func(rw http.ResponseWriter, req *http.Request, err interface{}) {
+	var size int
 
-		s.errorHandler(rw, req, fmt.Errorf("%v", err))
+	for _, h := range handlers {
+
+		size += len(h)
 	}
 
-	s.router.NotFound = s.notFoundHandler
-	s.router.MethodNotAllowed = s.methodNotAllowedHandler
+	result := make([]Handler, 0, size)
+
+	for _, h := range handlers {
+
+		result = append(result, h...)
+	}
+
+	return result
+}
+
+func (s *Server) acquire(rw http.ResponseWriter, req *http.Request, params httprouter.Params, r *route) *Context {
+
+	c := s.pool.Get().(*Context)
+
+	c.writer.ResponseWriter, c.writer.status = rw, http.StatusOK
+
+	c.Response = &c.writer
+	c.Request = req
+	c.ctx = req.Context()
+	c.server = s
+	c.route = r.pattern
+	c.params = params
+	c.handlers = r.handlers
+	c.index = -1
+
+	return c
+}
+
+// release returns c to the pool, the values map and the hook slices are
+// kept for the next request.
+func (s *Server) release(c *Context) {
+
+	var (
+		values = c.values
+		before = c.writer.before
+		after  = c.after
+	)
+
+	clear(values)
+	clear(before)
+	clear(after)
+
+	*c = Context{
+		values: values,
+		after:  after[:0],
+		writer: responseWriter{before: before[:0]},
+	}
+
+	s.pool.Put(c)
+}
+
+func (s *Server) preflight(path string) (httprouter.Handle, httprouter.Params) {
+
+	for _, r := range s.routes {
+
+		if handle, params, _ := s.preflights.Lookup(r.method, path); handle != nil {
+
+			return handle, params
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) allowed(path string) []string {
+
+	var (
+		methods = []string{"OPTIONS"}
+		seen    = map[string]bool{"OPTIONS": true}
+	)
+
+	for _, r := range s.routes {
+
+		if seen[r.method] {
+
+			continue
+		}
+
+		if handle, _, _ := s.router.Lookup(r.method, path); handle != nil {
+
+			methods = append(methods, r.method)
+		}
+
+		seen[r.method] = true
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
+
+// Build freezes the routing configuration, registering routes or changing
+// settings afterwards panics with ErrServerBuilt. It is called by the first
+// request or by Run, calling it again has no effect.
+func (s *Server) Build() {
+
+	s.build.Do(func() {
+
+		var (
+			notFound         = &route{handlers: combine(s.handlers, s.notFoundHandlers)}
+			methodNotAllowed = &route{handlers: combine(s.handlers, s.methodNotAllowedHandlers)}
+		)
+
+		// preflights mirrors the router with the preflight chain of every
+		// route, so an OPTIONS request runs the middleware of the route it
+		// matches.
+		s.preflights = httprouter.New()
+
+		for _, r := range s.routes {
+
+			preflight := &route{
+				method:   "OPTIONS",
+				pattern:  r.pattern,
+				handlers: combine(r.middleware, []Handler{preflightHandler}),
+			}
+
+			s.preflights.Handle(r.method, r.pattern, func(rw http.ResponseWriter, req *http.Request, params httprouter.Params) {
+
+				s.dispatch(rw, req, params, preflight)
+			})
+		}
+
+		// OPTIONS requests without an explicit route end up in NotFound,
+		// where they run the middleware of the route matching the path.
+		s.router.HandleOPTIONS = false
+
+		s.router.NotFound = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+
+			if req.Method == "OPTIONS" {
+
+				if handle, params := s.preflight(req.URL.Path); handle != nil {
+
+					handle(rw, req, params)
+
+					return
+				}
+			}
+
+			s.dispatch(rw, req, nil, notFound)
+		})
+
+		s.router.MethodNotAllowed = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+
+			if s.allowHeader {
+
+				rw.Header().Set("Allow", strings.Join(s.allowed(req.URL.Path), ", "))
+
+			} else {
+
+				rw.Header().Del("Allow")
+			}
+
+			s.dispatch(rw, req, nil, methodNotAllowed)
+		})
+
+		s.built.Store(true)
+	})
+}
+
+func (s *Server) mutable() {
+
+	if s.built.Load() {
+
+		panic(ErrServerBuilt)
+	}
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+
+	s.Build()
 
 	s.router.ServeHTTP(rw, req)
 }
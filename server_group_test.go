@@ -0,0 +1,122 @@
+package http200ok
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerGroup(t *testing.T) {
+
+	app := New()
+
+	api := app.Group("/api/v1")
+	api.Get("/users/:UserID/", func(c *Context) {
+
+		fmt.Fprint(c.Response, "User"+c.RequestParam("UserID"))
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/api/v1/users/42/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		if body, err := ioutil.ReadAll(res.Body); assert.NoError(t, err) {
+
+			assert.Equal(t, "User42", string(body))
+		}
+	}
+
+	if res, err := client.Get(ts.URL + "/users/42/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	}
+}
+
+func TestServerGroupMiddlewareOrder(t *testing.T) {
+
+	var order []string
+
+	app := New()
+	app.Use(func(_ *Context) {
+
+		order = append(order, "global")
+	})
+
+	admin := app.Group("/admin", func(_ *Context) {
+
+		order = append(order, "admin")
+	})
+
+	users := admin.Group("/users", func(_ *Context) {
+
+		order = append(order, "users")
+	})
+
+	users.Get("/", func(_ *Context) {
+
+		order = append(order, "handler")
+	})
+
+	app.Get("/", func(_ *Context) {
+
+		order = append(order, "public")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/admin/users/"); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, []string{"global", "admin", "users", "handler"}, order)
+		}
+	}
+
+	order = nil
+
+	if res, err := client.Get(ts.URL); assert.NoError(t, err) {
+
+		if assert.Equal(t, http.StatusOK, res.StatusCode) {
+
+			assert.Equal(t, []string{"global", "public"}, order)
+		}
+	}
+}
+
+func TestServerGroupStop(t *testing.T) {
+
+	var reached bool
+
+	app := New()
+
+	admin := app.Group("/admin", func(c *Context) {
+
+		http.Error(c.Response, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+		c.Stop()
+	})
+
+	admin.Get("/", func(_ *Context) {
+
+		reached = true
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/admin/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		assert.False(t, reached)
+	}
+}
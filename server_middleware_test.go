@@ -1,6 +1,7 @@
 package http200ok
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -152,3 +153,139 @@ func TestServerMiddlewareContext(t *testing.T) {
 		}
 	}
 }
+
+func TestServerMiddlewareNext(t *testing.T) {
+
+	var order []string
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		order = append(order, "A before")
+
+		c.Next()
+
+		order = append(order, "A after")
+	})
+
+	app.Use(func(c *Context) {
+
+		order = append(order, "B")
+	})
+
+	app.Get("/", func(c *Context) {
+
+		order = append(order, "C before")
+
+		c.Next()
+
+		order = append(order, "C after")
+	}, func(c *Context) {
+
+		order = append(order, "D")
+
+		c.Status(http.StatusCreated)
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, []string{"A before", "B", "C before", "D", "C after", "A after"}, order)
+}
+
+func TestServerMiddlewareAbort(t *testing.T) {
+
+	var (
+		after   bool
+		aborted bool
+		reached bool
+	)
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		c.Next()
+
+		after, aborted = true, c.IsAborted()
+	})
+
+	app.Get("/", func(c *Context) {
+
+		assert.False(t, c.IsAborted())
+
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}, func(c *Context) {
+
+		reached = true
+	})
+
+	app.Get("/json/", func(c *Context) {
+
+		c.AbortWithStatusJSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+	}, func(c *Context) {
+
+		reached = true
+	})
+
+	app.Get("/error/", func(c *Context) {
+
+		c.AbortWithError(http.StatusTeapot, errors.New("teapot"))
+	}, func(c *Context) {
+
+		reached = true
+	})
+
+	for path, code := range map[string]int{
+		"/":       http.StatusUnauthorized,
+		"/json/":  http.StatusForbidden,
+		"/error/": http.StatusTeapot,
+	} {
+
+		after, aborted = false, false
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, code, rw.Code, path)
+		assert.True(t, after, path)
+		assert.True(t, aborted, path)
+	}
+
+	assert.False(t, reached)
+}
+
+func TestServerMiddlewareSnapshot(t *testing.T) {
+
+	var used []string
+
+	app := New()
+	for i := 0; i < 3; i++ {
+
+		app.Use(func(_ *Context) {})
+	}
+
+	for _, url := range []string{"/a/", "/b/"} {
+
+		url := url
+
+		app.Get(url, func(_ *Context) {
+
+			used = append(used, url)
+		})
+	}
+
+	app.Use(func(c *Context) {
+
+		t.Error("middleware registered after the route must not run")
+	})
+
+	for _, url := range []string{"/a/", "/b/"} {
+
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+
+	assert.Equal(t, []string{"/a/", "/b/"}, used)
+}
@@ -193,20 +193,113 @@ func TestServerHead(t *testing.T) {
 	}
 }
 
+func TestServerPatch(t *testing.T) {
+
+	app := New()
+
+	app.Patch("/patch/", func(c *Context) {
+
+		fmt.Fprint(c.Response, "PatchTest")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("PATCH", ts.URL+"/patch/", bytes.NewReader([]byte{})); assert.NoError(t, err) {
+
+		res, err := client.Do(req)
+
+		if assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+
+			body, err := ioutil.ReadAll(res.Body)
+
+			if assert.NoError(t, err) {
+
+				assert.Contains(t, string(body), "PatchTest")
+			}
+		}
+	}
+}
+
+func TestServerOptions(t *testing.T) {
+
+	app := New()
+
+	app.Options("/options/", func(c *Context) {
+
+		c.Response.Header().Set("Allow", "OPTIONS")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("OPTIONS", ts.URL+"/options/", nil); assert.NoError(t, err) {
+
+		res, err := client.Do(req)
+
+		if assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "OPTIONS", res.Header.Get("Allow"))
+		}
+	}
+}
+
+func TestServerHandle(t *testing.T) {
+
+	var used bool
+
+	app := New()
+
+	app.Use(func(_ *Context) {
+
+		used = true
+	})
+
+	app.Handle("PROPFIND", "/dav/:Name", func(c *Context) {
+
+		c.Response.WriteHeader(http.StatusMultiStatus)
+
+		fmt.Fprint(c.Response, "Propfind"+c.RequestParam("Name"))
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if req, err := http.NewRequest("PROPFIND", ts.URL+"/dav/file", nil); assert.NoError(t, err) {
+
+		res, err := client.Do(req)
+
+		if assert.NoError(t, err) {
+
+			assert.Equal(t, http.StatusMultiStatus, res.StatusCode)
+			assert.True(t, used)
+
+			body, err := ioutil.ReadAll(res.Body)
+
+			if assert.NoError(t, err) {
+
+				assert.Equal(t, "Propfindfile", string(body))
+			}
+		}
+	}
+}
+
 func TestServerWebSocket(t *testing.T) {
 
 	type T struct {
 		Message string
 	}
 
-	var ws *websocket.Conn
-
 	app := New()
 
 	app.WebSocket("/ws/", func(c *Context) {
 
-		ws = c.WebSocket.Conn()
-
 		c.WebSocket.SendJSON(T{Message: "TestWebSocket"})
 	})
 
@@ -297,8 +390,8 @@ func TestServerCustomNotFound(t *testing.T) {
 
 	app := New()
 	app.Get("/", func(c *Context) {})
-	app.SetNotFoundHandler(func(rw http.ResponseWriter, req *http.Request) {
-		http.Error(rw, "Custom404", http.StatusNotFound)
+	app.SetNotFoundHandler(func(c *Context) {
+		http.Error(c.Response, "Custom404", http.StatusNotFound)
 	})
 
 	ts := httptest.NewServer(app)
@@ -344,8 +437,8 @@ func TestServerCustomMethodNotAllowed(t *testing.T) {
 	app := New()
 	app.Get("/", func(c *Context) {})
 	app.Post("/post/", func(c *Context) {})
-	app.SetMethodNotAllowedHandler(func(rw http.ResponseWriter, req *http.Request) {
-		http.Error(rw, "Custom405", http.StatusMethodNotAllowed)
+	app.SetMethodNotAllowedHandler(func(c *Context) {
+		http.Error(c.Response, "Custom405", http.StatusMethodNotAllowed)
 	})
 
 	ts := httptest.NewServer(app)
@@ -417,3 +510,89 @@ func TestServerCustomInternalServerError(t *testing.T) {
 		}
 	}
 }
+
+func TestServerFallbackMiddleware(t *testing.T) {
+
+	var used []string
+
+	app := New()
+	app.Use(RequestID(RequestIDConfig{}), func(c *Context) {
+
+		used = append(used, c.Request.URL.Path)
+	})
+
+	app.Get("/", func(c *Context) {})
+	app.Put("/", func(c *Context) {})
+	app.SetNotFoundHandler(func(c *Context) {
+
+		c.Error(NewHTTPError(http.StatusNotFound, "", nil))
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/404/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.NotEmpty(t, rw.Header().Get("X-Request-ID"))
+
+	rw = httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("POST", "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "GET, OPTIONS, PUT", rw.Header().Get("Allow"))
+	assert.NotEmpty(t, rw.Header().Get("X-Request-ID"))
+
+	assert.Equal(t, []string{"/404/", "/"}, used)
+}
+
+func TestServerAllowHeaderDisabled(t *testing.T) {
+
+	app := New()
+	app.SetAllowHeader(false)
+	app.Get("/", func(c *Context) {})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("POST", "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Empty(t, rw.Header().Get("Allow"))
+}
+
+func TestServerOptionsStaticNextToParam(t *testing.T) {
+
+	var used []string
+
+	app := New()
+	app.Use(func(c *Context) {
+
+		used = append(used, c.Request.Method+" "+c.Route())
+	})
+
+	assert.NotPanics(t, func() {
+
+		app.Post("/users/new", func(c *Context) {})
+		app.Get("/users/:id", func(c *Context) {})
+	})
+
+	for path, allow := range map[string]string{
+		"/users/new": "GET, OPTIONS, POST",
+		"/users/42":  "GET, OPTIONS",
+	} {
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("OPTIONS", path, nil))
+
+		assert.Equal(t, http.StatusNoContent, rw.Code, path)
+		assert.Equal(t, allow, rw.Header().Get("Allow"), path)
+	}
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("OPTIONS", "/missing/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.ElementsMatch(t, []string{"OPTIONS /users/new", "OPTIONS /users/:id", "OPTIONS "}, used)
+}
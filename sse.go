@@ -0,0 +1,266 @@
+package http200ok
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStreamClosed      = errors.New("http200ok: event stream is closed")
+	ErrStreamUnsupported = errors.New("http200ok: response writer does not support flushing")
+)
+
+type SSEConfig struct {
+	Retry     time.Duration
+	Heartbeat time.Duration
+}
+
+func (s *Server) SetSSEConfig(config SSEConfig) {
+	s.mutable()
+	s.sseConfig = config
+}
+
+func SSEOptions(config SSEConfig) Handler {
+
+	return func(c *Context) {
+
+		c.sseConfig = &config
+	}
+}
+
+type eventStream struct {
+	rw          http.ResponseWriter
+	flusher     http.Flusher
+	mutex       sync.Mutex
+	lastEventID string
+	once        sync.Once
+	done        chan struct{}
+}
+
+func (e *eventStream) LastEventID() string {
+
+	return e.lastEventID
+}
+
+// Done is closed when the client disconnects or the server shuts down.
+func (e *eventStream) Done() <-chan struct{} {
+
+	return e.done
+}
+
+func (e *eventStream) Send(event, id, data string) error {
+
+	var buf strings.Builder
+
+	if id != "" {
+
+		buf.WriteString("id: " + sanitizeField(id) + "\n")
+	}
+
+	if event != "" {
+
+		buf.WriteString("event: " + sanitizeField(event) + "\n")
+	}
+
+	// \r\n, \r and \n all end a line in the event stream grammar.
+	for _, line := range strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(data), "\n") {
+
+		buf.WriteString("data: " + line + "\n")
+	}
+
+	buf.WriteString("\n")
+
+	return e.write(buf.String())
+}
+
+func (e *eventStream) SendJSON(event, id string, v interface{}) error {
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+
+		return err
+	}
+
+	return e.Send(event, id, string(data))
+}
+
+func (e *eventStream) Retry(retry time.Duration) error {
+
+	return e.write(fmt.Sprintf("retry: %d\n\n", retry/time.Millisecond))
+}
+
+func (e *eventStream) Comment(text string) error {
+
+	return e.write(": " + sanitizeField(text) + "\n\n")
+}
+
+func (e *eventStream) write(text string) error {
+
+	e.mutex.Lock()
+
+	defer e.mutex.Unlock()
+
+	select {
+	case <-e.done:
+
+		return ErrStreamClosed
+
+	default:
+	}
+
+	if _, err := fmt.Fprint(e.rw, text); err != nil {
+
+		return err
+	}
+
+	e.flusher.Flush()
+
+	return nil
+}
+
+func (e *eventStream) heartbeat(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ticker.C:
+
+			if e.Comment("heartbeat") != nil {
+
+				return
+			}
+
+		case <-e.done:
+
+			return
+		}
+	}
+}
+
+// close waits for a write in progress, so nothing touches the response
+// once it returns.
+func (e *eventStream) close() {
+
+	e.mutex.Lock()
+
+	defer e.mutex.Unlock()
+
+	e.once.Do(func() {
+
+		close(e.done)
+	})
+}
+
+func sseHandlers(handlers []Handler) []Handler {
+
+	i := len(handlers) - 1
+
+	return append(handlers[:i:i], append([]Handler{sseMiddleware()}, handlers[i:]...)...)
+}
+
+func sseMiddleware() Handler {
+
+	return func(c *Context) {
+
+		if _, ok := c.Response.Unwrap().(http.Flusher); !ok {
+
+			c.Error(ErrStreamUnsupported)
+
+			return
+		}
+
+		config := c.server.sseConfig
+
+		if c.sseConfig != nil {
+
+			config = *c.sseConfig
+		}
+
+		stream := &eventStream{
+			rw:          c.Response,
+			flusher:     c.Response,
+			lastEventID: c.Request.Header.Get("Last-Event-ID"),
+			done:        make(chan struct{}),
+		}
+
+		if !c.server.trackStream(stream) {
+
+			c.Error(NewHTTPError(http.StatusServiceUnavailable, "", ErrStreamClosed))
+
+			return
+		}
+
+		var heartbeat sync.WaitGroup
+
+		defer c.server.untrackStream(stream)
+		defer heartbeat.Wait()
+		defer stream.close()
+
+		header := c.Response.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+
+		c.Response.WriteHeader(http.StatusOK)
+
+		if config.Retry > 0 {
+
+			stream.Retry(config.Retry)
+
+		} else {
+
+			c.Response.Flush()
+		}
+
+		cancel := c.withCancel()
+
+		defer cancel()
+
+		disconnected := c.Request.Context().Done()
+
+		go func() {
+
+			select {
+			case <-disconnected:
+
+				stream.close()
+
+			case <-stream.done:
+			}
+
+			cancel()
+		}()
+
+		if config.Heartbeat > 0 {
+
+			heartbeat.Add(1)
+
+			go func() {
+
+				defer heartbeat.Done()
+
+				stream.heartbeat(config.Heartbeat)
+			}()
+		}
+
+		c.Events = stream
+
+		c.Next()
+	}
+}
+
+func sanitizeField(value string) string {
+
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,189 @@
+package http200ok
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+
+	var lines []string
+
+	for {
+
+		line, err := reader.ReadString('\n')
+
+		if !assert.NoError(t, err) {
+
+			t.FailNow()
+		}
+
+		if line == "\n" {
+
+			return lines
+		}
+
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func TestServerSSE(t *testing.T) {
+
+	var lastEventID string
+
+	app := New()
+	app.SSE("/events/", SSEOptions(SSEConfig{Retry: 3 * time.Second}), func(c *Context) {
+
+		lastEventID = c.Events.LastEventID()
+
+		c.Events.Send("greeting", "1", "Hello\nWorld")
+		c.Events.SendJSON("", "2", map[string]int{"Count": 2})
+		c.Events.Send("msg", "", "x\revent: evil\r\ny")
+	})
+
+	ts := httptest.NewServer(app)
+
+	req, err := http.NewRequest("GET", ts.URL+"/events/", nil)
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	req.Header.Set("Last-Event-ID", "42")
+
+	if res, err := http.DefaultClient.Do(req); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+
+		reader := bufio.NewReader(res.Body)
+
+		assert.Equal(t, []string{"retry: 3000"}, readEvent(t, reader))
+		assert.Equal(t, []string{"id: 1", "event: greeting", "data: Hello", "data: World"}, readEvent(t, reader))
+		assert.Equal(t, []string{"id: 2", `data: {"Count":2}`}, readEvent(t, reader))
+		assert.Equal(t, []string{"event: msg", "data: x", "data: event: evil", "data: y"}, readEvent(t, reader))
+		assert.Equal(t, "42", lastEventID)
+	}
+}
+
+func TestServerSSEHeartbeatAndDisconnect(t *testing.T) {
+
+	finished := make(chan error, 1)
+
+	app := New()
+	app.SetSSEConfig(SSEConfig{Heartbeat: 10 * time.Millisecond})
+	app.SSE("/events/", func(c *Context) {
+
+		<-c.Events.Done()
+
+		finished <- c.Events.Send("", "", "Too late")
+	})
+
+	ts := httptest.NewServer(app)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	req, err := http.NewRequest("GET", ts.URL+"/events/", nil)
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	if res, err := http.DefaultClient.Do(req.WithContext(ctx)); assert.NoError(t, err) {
+
+		reader := bufio.NewReader(res.Body)
+
+		assert.Equal(t, []string{": heartbeat"}, readEvent(t, reader))
+
+		cancel()
+
+		assert.Equal(t, ErrStreamClosed, <-finished)
+	}
+}
+
+func TestServerSSEShutdown(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if !assert.NoError(t, err) {
+
+		return
+	}
+
+	connected := make(chan struct{})
+
+	app := New()
+	app.SSE("/events/", func(c *Context) {
+
+		close(connected)
+
+		<-c.Events.Done()
+	})
+
+	stopped := make(chan error, 1)
+
+	go func() {
+
+		stopped <- app.RunListener(listener)
+	}()
+
+	if res, err := http.Get("http://" + listener.Addr().String() + "/events/"); assert.NoError(t, err) {
+
+		defer res.Body.Close()
+
+		<-connected
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		defer cancel()
+
+		assert.NoError(t, app.Shutdown(ctx))
+		assert.NoError(t, <-stopped)
+	}
+}
+
+// TestServerSSEHeartbeatAfterReturn is meant to be run with -race, a
+// heartbeat must not write to the pooled Context after the handler returned.
+func TestServerSSEHeartbeatAfterReturn(t *testing.T) {
+
+	app := New()
+	app.SSE("/events/", SSEOptions(SSEConfig{Heartbeat: time.Millisecond}), func(c *Context) {
+
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	app.Get("/", func(c *Context) {
+
+		c.String(http.StatusOK, "Hello")
+	})
+
+	ts := httptest.NewServer(app)
+
+	defer ts.Close()
+
+	for i := 0; i < 5; i++ {
+
+		for _, path := range []string{"/events/", "/"} {
+
+			if res, err := http.Get(ts.URL + path); assert.NoError(t, err) {
+
+				ioutil.ReadAll(res.Body)
+
+				res.Body.Close()
+			}
+		}
+	}
+}
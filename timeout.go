@@ -0,0 +1,31 @@
+package http200ok
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout sets a deadline on the Context for the rest of the chain, a
+// chain that runs past it without writing a response or reporting an
+// error ends with 503.
+func Timeout(timeout time.Duration) Handler {
+
+	return func(c *Context) {
+
+		ctx, cancel := context.WithTimeout(c.context(), timeout)
+
+		defer cancel()
+
+		request := c.Request
+
+		c.ctx, c.Request = ctx, request.WithContext(ctx)
+
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && c.err == nil && !c.Response.Written() {
+
+			c.Error(NewHTTPError(http.StatusServiceUnavailable, "", ctx.Err()))
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package http200ok
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidRule is returned by Validate for an unknown rule or a bad rule
+// parameter, it is a programming error and not reported as a 422.
+var ErrInvalidRule = errors.New("http200ok: invalid validation rule")
+
+type ValidatorFunc func(v reflect.Value, param string) bool
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+
+	return e.Field + " " + e.Message
+}
+
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+
+	messages := make([]string, 0, len(e))
+
+	for _, err := range e {
+
+		messages = append(messages, err.Error())
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	validators = struct {
+		sync.RWMutex
+		funcs   map[string]ValidatorFunc
+		regexps map[string]*regexp.Regexp
+	}{
+		funcs:   make(map[string]ValidatorFunc),
+		regexps: make(map[string]*regexp.Regexp),
+	}
+)
+
+func RegisterValidator(name string, fn ValidatorFunc) {
+
+	validators.Lock()
+
+	validators.funcs[name] = fn
+
+	validators.Unlock()
+}
+
+func Validate(v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr {
+
+		if rv.IsNil() {
+
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+
+		return nil
+	}
+
+	var errs ValidationErrors
+
+	if err := validateStruct(rv, "", &errs); err != nil {
+
+		return err
+	}
+
+	if len(errs) != 0 {
+
+		return errs
+	}
+
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string, errs *ValidationErrors) error {
+
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+
+		field := rt.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous {
+
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+
+			if err := validateStruct(fv, prefix, errs); err != nil {
+
+				return err
+			}
+
+			continue
+		}
+
+		name := prefix + fieldName(field)
+
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+
+			if err := validateField(fv, name, tag, errs); err != nil {
+
+				return err
+			}
+		}
+
+		if err := validateNested(fv, name, errs); err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateNested(fv reflect.Value, name string, errs *ValidationErrors) error {
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+
+		if !fv.IsNil() {
+
+			return validateNested(fv.Elem(), name, errs)
+		}
+
+	case reflect.Struct:
+
+		return validateStruct(fv, name+".", errs)
+
+	case reflect.Slice, reflect.Array:
+
+		for i := 0; i < fv.Len(); i++ {
+
+			if err := validateNested(fv.Index(i), fmt.Sprintf("%s[%d]", name, i), errs); err != nil {
+
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateField checks every rule of the tag before looking at the value,
+// so a bad rule is reported even for a zero value. Rules apply to zero
+// values unless the tag has omitempty, a nil pointer only fails required
+// and a pointer to a zero value is validated.
+func validateField(fv reflect.Value, name, tag string, errs *ValidationErrors) error {
+
+	var (
+		rules     = splitRules(tag)
+		omitempty bool
+	)
+
+	for _, rule := range rules {
+
+		rule, param := splitRule(rule)
+
+		if rule == "omitempty" {
+
+			omitempty = true
+
+			continue
+		}
+
+		if err := checkRule(rule, param); err != nil {
+
+			return fmt.Errorf("%w: %s on %s: %v", ErrInvalidRule, rule, name, err)
+		}
+	}
+
+	if omitempty && fv.IsZero() {
+
+		return nil
+	}
+
+	for fv.Kind() == reflect.Ptr {
+
+		if fv.IsNil() {
+
+			if strings.Contains(","+tag+",", ",required,") {
+
+				*errs = append(*errs, FieldError{Field: name, Rule: "required", Message: "is required"})
+			}
+
+			return nil
+		}
+
+		fv = fv.Elem()
+	}
+
+	for _, rule := range rules {
+
+		rule, param := splitRule(rule)
+
+		if rule == "omitempty" {
+
+			continue
+		}
+
+		if message, ok := validateRule(fv, rule, param); !ok {
+
+			*errs = append(*errs, FieldError{Field: name, Rule: rule, Param: param, Message: message})
+		}
+	}
+
+	return nil
+}
+
+func checkRule(rule, param string) error {
+
+	switch rule {
+	case "required", "oneof", "email", "uuid":
+
+		return nil
+
+	case "min", "max", "len":
+
+		_, err := strconv.ParseFloat(param, 64)
+
+		return err
+
+	case "regexp":
+
+		_, err := compileRegexp(param)
+
+		return err
+	}
+
+	validators.RLock()
+
+	_, found := validators.funcs[rule]
+
+	validators.RUnlock()
+
+	if !found {
+
+		return errors.New("unknown rule")
+	}
+
+	return nil
+}
+
+// validateRule expects a rule accepted by checkRule.
+func validateRule(fv reflect.Value, rule, param string) (string, bool) {
+
+	switch rule {
+	case "required":
+
+		return "is required", !fv.IsZero()
+
+	case "min", "max", "len":
+
+		n, _ := strconv.ParseFloat(param, 64)
+
+		size, isLength := measure(fv)
+
+		switch {
+		case rule == "min" && size < n && isLength:
+
+			return fmt.Sprintf("must have at least %s elements", param), false
+
+		case rule == "min" && size < n:
+
+			return fmt.Sprintf("must be at least %s", param), false
+
+		case rule == "max" && size > n && isLength:
+
+			return fmt.Sprintf("must have at most %s elements", param), false
+
+		case rule == "max" && size > n:
+
+			return fmt.Sprintf("must be at most %s", param), false
+
+		case rule == "len" && size != n && isLength:
+
+			return fmt.Sprintf("must have exactly %s elements", param), false
+
+		case rule == "len" && size != n:
+
+			return fmt.Sprintf("must be exactly %s", param), false
+		}
+
+		return "", true
+
+	case "oneof":
+
+		value := fmt.Sprint(fv.Interface())
+
+		for _, allowed := range strings.Fields(param) {
+
+			if value == allowed {
+
+				return "", true
+			}
+		}
+
+		return fmt.Sprintf("must be one of [%s]", param), false
+
+	case "email":
+
+		address, err := mail.ParseAddress(fv.String())
+
+		return "must be a valid email address", fv.Kind() == reflect.String && err == nil && address.Address == fv.String()
+
+	case "uuid":
+
+		return "must be a valid UUID", fv.Kind() == reflect.String && uuidRegexp.MatchString(fv.String())
+
+	case "regexp":
+
+		re, _ := compileRegexp(param)
+
+		return fmt.Sprintf("must match %s", param), fv.Kind() == reflect.String && re.MatchString(fv.String())
+	}
+
+	validators.RLock()
+
+	fn := validators.funcs[rule]
+
+	validators.RUnlock()
+
+	return "is invalid", fn(fv, param)
+}
+
+func measure(fv reflect.Value) (float64, bool) {
+
+	switch fv.Kind() {
+	case reflect.String:
+
+		return float64(utf8.RuneCountInString(fv.String())), true
+
+	case reflect.Slice, reflect.Array, reflect.Map:
+
+		return float64(fv.Len()), true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		return float64(fv.Int()), false
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		return float64(fv.Uint()), false
+
+	case reflect.Float32, reflect.Float64:
+
+		return fv.Float(), false
+	}
+
+	return 0, false
+}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+
+	validators.RLock()
+
+	re, found := validators.regexps[pattern]
+
+	validators.RUnlock()
+
+	if found {
+
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+
+	if err != nil {
+
+		return nil, err
+	}
+
+	validators.Lock()
+
+	validators.regexps[pattern] = re
+
+	validators.Unlock()
+
+	return re, nil
+}
+
+// splitRules splits a validate tag on commas, a regexp rule is always
+// the last one and keeps the rest of the tag as its pattern.
+func splitRules(tag string) []string {
+
+	var rules []string
+
+	for tag != "" {
+
+		if strings.HasPrefix(tag, "regexp=") {
+
+			return append(rules, tag)
+		}
+
+		i := strings.IndexByte(tag, ',')
+
+		if i == -1 {
+
+			return append(rules, tag)
+		}
+
+		rules, tag = append(rules, tag[:i]), tag[i+1:]
+	}
+
+	return rules
+}
+
+func splitRule(rule string) (string, string) {
+
+	if i := strings.IndexByte(rule, '='); i != -1 {
+
+		return rule[:i], rule[i+1:]
+	}
+
+	return rule, ""
+}
+
+func fieldName(field reflect.StructField) string {
+
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+
+		return name
+	}
+
+	return field.Name
+}
@@ -0,0 +1,221 @@
+package http200ok
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validateTestUser struct {
+	ID       string                `json:"id" validate:"uuid"`
+	Name     string                `json:"name" validate:"required,min=3,max=10"`
+	Email    string                `json:"email" validate:"required,email"`
+	Age      int                   `json:"age" validate:"min=18,max=130"`
+	Role     string                `json:"role" validate:"oneof=admin user"`
+	Code     string                `json:"code" validate:"len=4,regexp=^[A-Z]{2,4}$"`
+	Nickname *string               `json:"nickname" validate:"required"`
+	Address  validateTestAddress   `json:"address"`
+	Backup   []validateTestAddress `json:"backup"`
+	Even     int                   `json:"even" validate:"even"`
+}
+
+func TestValidate(t *testing.T) {
+
+	RegisterValidator("even", func(v reflect.Value, _ string) bool {
+
+		return v.Int()%2 == 0
+	})
+
+	nickname := "nick"
+
+	valid := validateTestUser{
+		ID:       "c9f2a7e4-5b6d-4c1e-9a3f-2d8b7e6f1a0c",
+		Name:     "Alice",
+		Email:    "alice@example.com",
+		Age:      30,
+		Role:     "admin",
+		Code:     "ABCD",
+		Nickname: &nickname,
+		Address:  validateTestAddress{City: "Paris"},
+		Even:     2,
+	}
+
+	assert.NoError(t, Validate(&valid))
+
+	invalid := validateTestUser{
+		ID:     "not-a-uuid",
+		Name:   "Al",
+		Email:  "alice",
+		Age:    12,
+		Role:   "root",
+		Code:   "ab",
+		Backup: []validateTestAddress{{}},
+		Even:   3,
+	}
+
+	err := Validate(invalid)
+
+	if errs, ok := err.(ValidationErrors); assert.True(t, ok) {
+
+		fields := make(map[string]string)
+
+		for _, e := range errs {
+
+			fields[e.Field+":"+e.Rule] = e.Message
+		}
+
+		for _, expected := range []string{
+			"id:uuid",
+			"name:min",
+			"email:email",
+			"age:min",
+			"role:oneof",
+			"code:len",
+			"code:regexp",
+			"nickname:required",
+			"address.city:required",
+			"backup[0].city:required",
+			"even:even",
+		} {
+
+			assert.Contains(t, fields, expected)
+		}
+
+		assert.Len(t, errs, 11)
+	}
+}
+
+func TestValidateNumericLen(t *testing.T) {
+
+	var v struct {
+		Digits int `json:"digits" validate:"len=4"`
+	}
+
+	v.Digits = 12
+
+	if errs, ok := Validate(&v).(ValidationErrors); assert.True(t, ok) && assert.Len(t, errs, 1) {
+
+		assert.Equal(t, "must be exactly 4", errs[0].Message)
+	}
+}
+
+func TestValidateZeroValues(t *testing.T) {
+
+	type T struct {
+		Count    int    `validate:"min=1"`
+		Kind     string `validate:"oneof=a b"`
+		Optional string `validate:"omitempty,email"`
+		Limit    *int   `validate:"omitempty,min=1"`
+	}
+
+	if errs, ok := Validate(&T{}).(ValidationErrors); assert.True(t, ok) && assert.Len(t, errs, 2) {
+
+		assert.Equal(t, "Count", errs[0].Field)
+		assert.Equal(t, "Kind", errs[1].Field)
+	}
+
+	zero := 0
+
+	if errs, ok := Validate(&T{Count: 1, Kind: "a", Optional: "bob", Limit: &zero}).(ValidationErrors); assert.True(t, ok) && assert.Len(t, errs, 2) {
+
+		assert.Equal(t, "Optional", errs[0].Field)
+		assert.Equal(t, "Limit", errs[1].Field)
+	}
+
+	var unknown struct {
+		Name *string `validate:"omitempty,unknown"`
+	}
+
+	assert.True(t, errors.Is(Validate(&unknown), ErrInvalidRule))
+}
+
+func TestValidateInvalidRule(t *testing.T) {
+
+	var (
+		unknown struct {
+			Name string `validate:"required,unknown"`
+		}
+		param struct {
+			Name string `validate:"min=three"`
+		}
+		pattern struct {
+			Name string `validate:"regexp=^[A-Z"`
+		}
+	)
+
+	unknown.Name, param.Name, pattern.Name = "Bob", "Bob", "Bob"
+
+	for _, v := range []interface{}{&unknown, &param, &pattern} {
+
+		err := Validate(v)
+
+		assert.True(t, errors.Is(err, ErrInvalidRule), err)
+
+		_, ok := err.(ValidationErrors)
+
+		assert.False(t, ok)
+	}
+
+	app := New()
+	app.Post("/", Wrap(func(c *Context) error {
+
+		return c.Bind(&unknown)
+	}))
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"Name":"Bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+}
+
+func TestContextBindValidation(t *testing.T) {
+
+	type Request struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	app := New()
+	app.Post("/", Wrap(func(c *Context) error {
+
+		var req Request
+
+		return c.Bind(&req)
+	}))
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Post(ts.URL, "application/json", strings.NewReader(`{}`)); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
+
+		var body struct {
+			Errors []FieldError `json:"errors"`
+		}
+
+		if assert.NoError(t, json.NewDecoder(res.Body).Decode(&body)) && assert.Len(t, body.Errors, 1) {
+
+			assert.Equal(t, FieldError{Field: "name", Rule: "required", Message: "is required"}, body.Errors[0])
+		}
+	}
+
+	if res, err := client.Post(ts.URL, "application/json", strings.NewReader(`{"name":"Bob"}`)); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
@@ -0,0 +1,130 @@
+package http200ok
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrKeyNotFound = errors.New("http200ok: key not found")
+
+// Key is a typed key for values stored on Context. Every key created with
+// NewKey is distinct, even if two packages use the same name.
+type Key[T any] struct {
+	name string
+}
+
+func NewKey[T any](name string) *Key[T] {
+
+	return &Key[T]{name: name}
+}
+
+func (k *Key[T]) String() string {
+
+	return k.name
+}
+
+func (k *Key[T]) keyName() string {
+
+	return k.name
+}
+
+type valueKey interface {
+	keyName() string
+}
+
+func SetValue[T any](c *Context, key *Key[T], value T) {
+
+	c.store(key, value)
+}
+
+func Value[T any](c *Context, key *Key[T]) (T, bool) {
+
+	v, found := c.lookup(key)
+
+	value, ok := v.(T)
+
+	return value, found && ok
+}
+
+// MustValue is like Value, a missing key is reported through c.Error and
+// the zero value is returned, the current handler keeps running unless it
+// checks c.IsAborted().
+func MustValue[T any](c *Context, key *Key[T]) T {
+
+	value, ok := Value(c, key)
+
+	if !ok {
+
+		c.Error(fmt.Errorf("%w: %s", ErrKeyNotFound, key.name))
+	}
+
+	return value
+}
+
+// MustGet is like Get, a missing key is reported through c.Error and nil
+// is returned, the current handler keeps running unless it checks
+// c.IsAborted(). A panic from asserting the nil still reports ErrKeyNotFound.
+func (c *Context) MustGet(key string) interface{} {
+
+	v, found := c.lookup(key)
+
+	if !found {
+
+		c.Error(fmt.Errorf("%w: %s", ErrKeyNotFound, key))
+	}
+
+	return v
+}
+
+// Keys returns the sorted names of all values stored on the Context.
+func (c *Context) Keys() []string {
+
+	c.mutex.Lock()
+
+	keys := make([]string, 0, len(c.values))
+
+	for key := range c.values {
+
+		switch k := key.(type) {
+		case string:
+
+			keys = append(keys, k)
+
+		case valueKey:
+
+			keys = append(keys, k.keyName())
+		}
+	}
+
+	c.mutex.Unlock()
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *Context) store(key, value interface{}) {
+
+	c.mutex.Lock()
+
+	if c.values == nil {
+
+		c.values = make(map[interface{}]interface{})
+	}
+
+	c.values[key] = value
+
+	c.mutex.Unlock()
+}
+
+func (c *Context) lookup(key interface{}) (interface{}, bool) {
+
+	defer c.mutex.Unlock()
+
+	c.mutex.Lock()
+
+	v, found := c.values[key]
+
+	return v, found
+}
@@ -0,0 +1,102 @@
+package http200ok
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testUser struct {
+	UserID int
+}
+
+func TestContextTypedValues(t *testing.T) {
+
+	var (
+		user    = NewKey[*testUser]("CurrentUser")
+		shadow  = NewKey[string]("CurrentUser")
+		missing = NewKey[int]("Missing")
+	)
+
+	app := New()
+	app.Get("/", func(c *Context) {
+
+		c.Set("CurrentUser", "legacy")
+
+		SetValue(c, user, &testUser{UserID: 1})
+		SetValue(c, shadow, "shadow")
+
+		if v, ok := Value(c, user); assert.True(t, ok) {
+
+			assert.Equal(t, 1, v.UserID)
+		}
+
+		if v, ok := Value(c, shadow); assert.True(t, ok) {
+
+			assert.Equal(t, "shadow", v)
+		}
+
+		v, ok := Value(c, missing)
+
+		assert.False(t, ok)
+		assert.Equal(t, 0, v)
+
+		assert.Equal(t, "legacy", c.Get("CurrentUser"))
+		assert.Equal(t, "legacy", c.MustGet("CurrentUser"))
+		assert.Equal(t, 1, c.Value(user).(*testUser).UserID)
+		assert.Equal(t, []string{"CurrentUser", "CurrentUser", "CurrentUser"}, c.Keys())
+	})
+
+	rw := httptest.NewRecorder()
+
+	app.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestContextMustGet(t *testing.T) {
+
+	var handled error
+
+	app := New()
+	app.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error) {
+
+		handled = err
+
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+
+	app.Get("/typed/", func(c *Context) {
+
+		MustValue(c, NewKey[*testUser]("CurrentUser"))
+	})
+
+	app.Get("/string/", func(c *Context) {
+
+		c.MustGet("CurrentUser")
+	}, func(c *Context) {
+
+		t.Error("the chain must stop after MustGet failed")
+	})
+
+	app.Get("/assert/", func(c *Context) {
+
+		user := c.MustGet("CurrentUser").(*testUser)
+
+		t.Error("the assertion must panic", user)
+	})
+
+	for _, path := range []string{"/typed/", "/string/", "/assert/"} {
+
+		handled = nil
+
+		rw := httptest.NewRecorder()
+
+		app.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.True(t, errors.Is(handled, ErrKeyNotFound), path)
+	}
+}
@@ -1,11 +1,186 @@
 package http200ok
 
 import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"golang.org/x/net/websocket"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
 )
 
+const (
+	TextMessage   = int(websocket.TextFrame)
+	BinaryMessage = int(websocket.BinaryFrame)
+)
+
+const (
+	CloseNormalClosure     = 1000
+	CloseGoingAway         = 1001
+	CloseProtocolError     = 1002
+	CloseUnsupportedData   = 1003
+	CloseNoStatusReceived  = 1005
+	CloseInvalidPayload    = 1007
+	ClosePolicyViolation   = 1008
+	CloseMessageTooBig     = 1009
+	CloseInternalServerErr = 1011
+)
+
+var (
+	ErrWebSocketClosed = errors.New("http200ok: websocket is closed")
+	ErrMessageTooBig   = errors.New("http200ok: websocket message is too big")
+	ErrPongTimeout     = errors.New("http200ok: websocket pong timeout")
+)
+
+const defaultReceiveQueueSize = 16
+
+type WebSocketConfig struct {
+	// PingInterval enables keepalive, the connection is terminated when
+	// the peer does not answer a ping within PongTimeout.
+	PingInterval   time.Duration
+	PongTimeout    time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	MaxMessageSize int64
+	// ReceiveQueueSize bounds the messages read ahead while keepalive is
+	// enabled, a peer that overflows it is closed with 1008.
+	ReceiveQueueSize int
+
+	// AllowOrigins is checked against the Origin header before the upgrade,
+	// requests without Origin are accepted.
+	AllowOrigins []string
+	// Protocols lists supported subprotocols in order of preference.
+	Protocols []string
+	// Handshake runs before the upgrade, a returned *HTTPError is sent
+	// with its status code.
+	Handshake func(c *Context) error
+
+	allowOrigin func(origin string) bool
+}
+
+// SetWebSocketConfig panics if an AllowOrigins entry cannot be compiled.
+func (s *Server) SetWebSocketConfig(config WebSocketConfig) {
+	s.mutable()
+	s.webSocketConfig = config.compile()
+}
+
+// WebSocketOptions panics if an AllowOrigins entry cannot be compiled.
+func WebSocketOptions(config WebSocketConfig) Handler {
+
+	config = config.compile()
+
+	return func(c *Context) {
+
+		c.wsConfig = &config
+	}
+}
+
+// compile builds the origin matcher once, instead of on every upgrade.
+func (config WebSocketConfig) compile() WebSocketConfig {
+
+	config.allowOrigin = nil
+
+	if len(config.AllowOrigins) != 0 {
+
+		allowOrigin, err := originMatcher(config.AllowOrigins, nil, nil)
+
+		if err != nil {
+
+			panic(fmt.Errorf("websocket: %w", err))
+		}
+
+		config.allowOrigin = allowOrigin
+	}
+
+	return config
+}
+
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+
+	if e.Reason != "" {
+
+		return fmt.Sprintf("websocket: close %d %s", e.Code, e.Reason)
+	}
+
+	return fmt.Sprintf("websocket: close %d", e.Code)
+}
+
+var (
+	closeCodec = websocket.Codec{
+		Marshal: func(v interface{}) ([]byte, byte, error) {
+
+			return v.([]byte), websocket.CloseFrame, nil
+		},
+	}
+
+	pingCodec = websocket.Codec{
+		Marshal: func(v interface{}) ([]byte, byte, error) {
+
+			return v.([]byte), websocket.PingFrame, nil
+		},
+	}
+)
+
+type wsMessage struct {
+	msgType int
+	data    []byte
+	err     error
+}
+
 type webSocket struct {
-	ws *websocket.Conn
+	ws       *websocket.Conn
+	config   WebSocketConfig
+	protocol string
+	rmutex   sync.Mutex
+	mutex    sync.Mutex
+	closed   bool
+	once     sync.Once
+	done     chan struct{}
+	pong     chan struct{}
+	messages chan wsMessage
+	readErr  error
+	cancel   context.CancelFunc
+}
+
+func newWebSocket(ws *websocket.Conn, config WebSocketConfig, cancel context.CancelFunc) *webSocket {
+
+	w := &webSocket{
+		ws:     ws,
+		config: config,
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	if config.PingInterval > 0 {
+
+		if w.config.PongTimeout <= 0 {
+
+			w.config.PongTimeout = config.PingInterval
+		}
+
+		if w.config.ReceiveQueueSize <= 0 {
+
+			w.config.ReceiveQueueSize = defaultReceiveQueueSize
+		}
+
+		w.pong = make(chan struct{}, 1)
+		w.messages = make(chan wsMessage, w.config.ReceiveQueueSize)
+
+		go w.readLoop()
+		go w.keepalive()
+	}
+
+	return w
 }
 
 func (w *webSocket) Conn() *websocket.Conn {
@@ -13,20 +188,432 @@ func (w *webSocket) Conn() *websocket.Conn {
 	return w.ws
 }
 
+func (w *webSocket) Protocol() string {
+
+	return w.protocol
+}
+
 func (w *webSocket) SendJSON(v interface{}) error {
 
-	return websocket.JSON.Send(w.ws, v)
+	if w.isClosed() {
+
+		return ErrWebSocketClosed
+	}
+
+	return w.send(websocket.JSON, v)
+}
+
+func (w *webSocket) SendText(text string) error {
+
+	if w.isClosed() {
+
+		return ErrWebSocketClosed
+	}
+
+	return w.send(websocket.Message, text)
+}
+
+func (w *webSocket) SendBinary(data []byte) error {
+
+	if w.isClosed() {
+
+		return ErrWebSocketClosed
+	}
+
+	return w.send(websocket.Message, data)
+}
+
+func (w *webSocket) ReceiveJSON(v interface{}) error {
+
+	_, data, err := w.Receive()
+
+	if err != nil {
+
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Receive returns the next text or binary message, control frames are
+// answered on the way. When the peer closes the connection the close
+// frame is echoed and a *CloseError is returned.
+func (w *webSocket) Receive() (int, []byte, error) {
+
+	w.rmutex.Lock()
+
+	defer w.rmutex.Unlock()
+
+	if w.messages == nil {
+
+		return w.read()
+	}
+
+	if w.readErr != nil {
+
+		return 0, nil, w.readErr
+	}
+
+	select {
+	case m := <-w.messages:
+
+		w.readErr = m.err
+
+		return m.msgType, m.data, m.err
+
+	case <-w.done:
+
+		w.readErr = ErrWebSocketClosed
+
+		return 0, nil, w.readErr
+	}
+}
+
+// Done is closed when the connection is terminated.
+func (w *webSocket) Done() <-chan struct{} {
+
+	return w.done
+}
+
+func (w *webSocket) read() (int, []byte, error) {
+
+	for {
+
+		if w.config.ReadTimeout > 0 {
+
+			w.ws.SetReadDeadline(time.Now().Add(w.config.ReadTimeout))
+		}
+
+		frame, err := w.ws.NewFrameReader()
+
+		if err != nil {
+
+			w.cancel()
+
+			return 0, nil, err
+		}
+
+		switch frame.PayloadType() {
+		case websocket.CloseFrame:
+
+			payload, _ := ioutil.ReadAll(frame)
+
+			closeErr := &CloseError{Code: CloseNoStatusReceived}
+
+			if len(payload) >= 2 {
+
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+
+			code := closeErr.Code
+
+			if code == CloseNoStatusReceived {
+
+				code = CloseNormalClosure
+			}
+
+			w.Close(code, "")
+
+			return 0, nil, closeErr
+
+		case websocket.PongFrame:
+
+			select {
+			case w.pong <- struct{}{}:
+			default:
+			}
+		}
+
+		if frame, err = w.ws.HandleFrame(frame); err != nil {
+
+			return 0, nil, err
+		}
+
+		if frame == nil {
+
+			continue
+		}
+
+		var reader io.Reader = frame
+
+		if w.config.MaxMessageSize > 0 {
+
+			reader = io.LimitReader(frame, w.config.MaxMessageSize+1)
+		}
+
+		data, err := ioutil.ReadAll(reader)
+
+		if err != nil {
+
+			return 0, nil, err
+		}
+
+		if w.config.MaxMessageSize > 0 && int64(len(data)) > w.config.MaxMessageSize {
+
+			w.Close(CloseMessageTooBig, "")
+
+			return 0, nil, ErrMessageTooBig
+		}
+
+		return int(frame.PayloadType()), data, nil
+	}
+}
+
+// readLoop never blocks on the handler, so pongs keep reaching keepalive
+// while received messages wait in the queue.
+func (w *webSocket) readLoop() {
+
+	for {
+
+		msgType, data, err := w.read()
+
+		if err != nil {
+
+			select {
+			case w.messages <- wsMessage{err: err}:
+			case <-w.done:
+			}
+
+			return
+		}
+
+		select {
+		case w.messages <- wsMessage{msgType: msgType, data: data}:
+		case <-w.done:
+
+			return
+
+		default:
+
+			w.Close(ClosePolicyViolation, "receive queue overflow")
+		}
+	}
+}
+
+func (w *webSocket) keepalive() {
+
+	ticker := time.NewTicker(w.config.PingInterval)
+
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ticker.C:
+		case <-w.done:
+
+			return
+		}
+
+		if err := w.send(pingCodec, []byte{}); err != nil {
+
+			w.terminate()
+
+			return
+		}
+
+		timeout := time.NewTimer(w.config.PongTimeout)
+
+		select {
+		case <-w.pong:
+
+			timeout.Stop()
+
+		case <-timeout.C:
+
+			w.terminate()
+
+			return
+
+		case <-w.done:
+
+			timeout.Stop()
+
+			return
+		}
+	}
+}
+
+// terminate drops a connection whose peer went silent.
+func (w *webSocket) terminate() {
+
+	w.mutex.Lock()
+
+	w.closed = true
+
+	w.mutex.Unlock()
+
+	w.ws.SetDeadline(time.Now().Add(time.Second))
+	w.ws.Close()
+
+	w.finish()
+}
+
+func (w *webSocket) finish() {
+
+	w.once.Do(func() {
+
+		close(w.done)
+	})
+
+	w.cancel()
+}
+
+func (w *webSocket) send(codec websocket.Codec, v interface{}) error {
+
+	if w.config.WriteTimeout > 0 {
+
+		w.ws.SetWriteDeadline(time.Now().Add(w.config.WriteTimeout))
+	}
+
+	return codec.Send(w.ws, v)
+}
+
+// OnMessage calls handler for every received message until the peer closes
+// the connection, an error returned by handler closes it with 1011.
+func (w *webSocket) OnMessage(handler func(msgType int, data []byte) error) error {
+
+	for {
+
+		msgType, data, err := w.Receive()
+
+		if err != nil {
+
+			var closeErr *CloseError
+
+			if errors.As(err, &closeErr) && (closeErr.Code == CloseNormalClosure || closeErr.Code == CloseGoingAway || closeErr.Code == CloseNoStatusReceived) {
+
+				return nil
+			}
+
+			return err
+		}
+
+		if err := handler(msgType, data); err != nil {
+
+			w.Close(CloseInternalServerErr, "")
+
+			return err
+		}
+	}
+}
+
+func (w *webSocket) Close(code int, reason string) error {
+
+	w.mutex.Lock()
+
+	if w.closed {
+
+		w.mutex.Unlock()
+
+		return nil
+	}
+
+	w.closed = true
+
+	w.mutex.Unlock()
+
+	payload := make([]byte, 2+len(reason))
+
+	binary.BigEndian.PutUint16(payload, uint16(code))
+
+	copy(payload[2:], reason)
+
+	defer w.cancel()
+
+	return w.send(closeCodec, payload)
+}
+
+func (w *webSocket) isClosed() bool {
+
+	w.mutex.Lock()
+
+	defer w.mutex.Unlock()
+
+	return w.closed
+}
+
+func wsHandlers(handlers []Handler) []Handler {
+
+	i := len(handlers) - 1
+
+	return append(handlers[:i:i], append([]Handler{wsMiddleware()}, handlers[i:]...)...)
 }
 
 func wsMiddleware() Handler {
 
 	return func(c *Context) {
 
+		config := c.server.webSocketConfig
+
+		if c.wsConfig != nil {
+
+			config = *c.wsConfig
+		}
+
+		if origin := c.Request.Header.Get("Origin"); origin != "" && config.allowOrigin != nil {
+
+			if !config.allowOrigin(origin) {
+
+				c.Error(NewHTTPError(http.StatusForbidden, "", fmt.Errorf("websocket: origin %q is not allowed", origin)))
+
+				return
+			}
+		}
+
+		if config.Handshake != nil {
+
+			if err := config.Handshake(c); err != nil {
+
+				c.Error(err)
+
+				return
+			}
+		}
+
 		wss := websocket.Server{
 
+			Handshake: func(wsConfig *websocket.Config, _ *http.Request) error {
+
+				wsConfig.Protocol = negotiateProtocol(wsConfig.Protocol, config.Protocols)
+
+				return nil
+			},
+
 			Handler: func(ws *websocket.Conn) {
 
-				c.WebSocket = webSocket{ws: ws}
+				cancel := c.withCancel()
+
+				socket := newWebSocket(ws, config, cancel)
+
+				defer socket.finish()
+
+				if protocols := ws.Config().Protocol; len(protocols) != 0 {
+
+					socket.protocol = protocols[0]
+				}
+
+				if !c.server.trackSocket(socket) {
+
+					socket.Close(CloseGoingAway, "")
+
+					return
+				}
+
+				defer c.server.untrackSocket(socket)
+
+				defer func() {
+
+					if rcv := recover(); rcv != nil {
+
+						socket.Close(CloseInternalServerErr, "")
+
+						panic(c.panicError(rcv))
+					}
+				}()
+
+				c.WebSocket = socket
 
 				c.Next()
 			},
@@ -34,9 +621,25 @@ func wsMiddleware() Handler {
 
 		wss.ServeHTTP(c.Response, c.Request)
 
-		if c.WebSocket.ws == nil {
+		if c.WebSocket == nil {
 
 			c.Stop()
 		}
 	}
 }
+
+func negotiateProtocol(requested, supported []string) []string {
+
+	for _, protocol := range supported {
+
+		for _, candidate := range requested {
+
+			if protocol == candidate {
+
+				return []string{protocol}
+			}
+		}
+	}
+
+	return nil
+}
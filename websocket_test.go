@@ -0,0 +1,419 @@
+package http200ok
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dialWebSocket(t *testing.T, ts *httptest.Server, path string) *websocket.Conn {
+
+	ws, err := websocket.Dial(strings.Replace(ts.URL, "http://", "ws://", 1)+path, "", ts.URL)
+
+	if !assert.NoError(t, err) {
+
+		t.FailNow()
+	}
+
+	return ws
+}
+
+func TestWebSocketOnMessage(t *testing.T) {
+
+	done := make(chan error, 1)
+
+	app := New()
+	app.WebSocket("/echo/", func(c *Context) {
+
+		done <- c.WebSocket.OnMessage(func(msgType int, data []byte) error {
+
+			if msgType == BinaryMessage {
+
+				return c.WebSocket.SendBinary(data)
+			}
+
+			return c.WebSocket.SendText("echo: " + string(data))
+		})
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/echo/")
+
+	var (
+		text string
+		data []byte
+	)
+
+	if assert.NoError(t, websocket.Message.Send(ws, "Hello")) && assert.NoError(t, websocket.Message.Receive(ws, &text)) {
+
+		assert.Equal(t, "echo: Hello", text)
+	}
+
+	if assert.NoError(t, websocket.Message.Send(ws, []byte{1, 2, 3})) && assert.NoError(t, websocket.Message.Receive(ws, &data)) {
+
+		assert.Equal(t, []byte{1, 2, 3}, data)
+	}
+
+	ws.Close()
+
+	assert.NoError(t, <-done)
+}
+
+func TestWebSocketReceiveJSON(t *testing.T) {
+
+	type Message struct {
+		Message string
+	}
+
+	received := make(chan Message, 1)
+
+	app := New()
+	app.WebSocket("/ws/", func(c *Context) {
+
+		var message Message
+
+		if assert.NoError(t, c.WebSocket.ReceiveJSON(&message)) {
+
+			received <- message
+		}
+
+		_, _, err := c.WebSocket.Receive()
+
+		var closeErr *CloseError
+
+		if assert.True(t, errors.As(err, &closeErr)) {
+
+			assert.Equal(t, CloseNormalClosure, closeErr.Code)
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	if assert.NoError(t, websocket.JSON.Send(ws, Message{Message: "JSON"})) {
+
+		assert.Equal(t, "JSON", (<-received).Message)
+	}
+
+	ws.Close()
+}
+
+func TestWebSocketClose(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", func(c *Context) {
+
+		assert.NoError(t, c.WebSocket.Close(ClosePolicyViolation, "Go away"))
+		assert.Equal(t, ErrWebSocketClosed, c.WebSocket.SendText("Too late"))
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	if frame, err := ws.NewFrameReader(); assert.NoError(t, err) {
+
+		assert.Equal(t, byte(websocket.CloseFrame), frame.PayloadType())
+
+		if payload, err := ioutil.ReadAll(frame); assert.NoError(t, err) && assert.True(t, len(payload) > 2) {
+
+			assert.Equal(t, ClosePolicyViolation, int(binary.BigEndian.Uint16(payload)))
+			assert.Equal(t, "Go away", string(payload[2:]))
+		}
+	}
+}
+
+func TestWebSocketKeepaliveTerminatesSilentPeer(t *testing.T) {
+
+	terminated := make(chan bool, 1)
+
+	app := New()
+	app.SetWebSocketConfig(WebSocketConfig{
+		PingInterval: 20 * time.Millisecond,
+		PongTimeout:  20 * time.Millisecond,
+	})
+
+	app.WebSocket("/ws/", func(c *Context) {
+
+		select {
+		case <-c.WebSocket.Done():
+
+			terminated <- true
+
+		case <-time.After(5 * time.Second):
+
+			terminated <- false
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	defer ws.Close()
+
+	assert.True(t, <-terminated)
+}
+
+func TestWebSocketKeepaliveAlivePeer(t *testing.T) {
+
+	received := make(chan string, 1)
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{
+		PingInterval: 10 * time.Millisecond,
+		PongTimeout:  50 * time.Millisecond,
+	}), func(c *Context) {
+
+		_, data, err := c.WebSocket.Receive()
+
+		if assert.NoError(t, err) {
+
+			received <- string(data)
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	defer ws.Close()
+
+	go func() {
+
+		var message string
+
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if assert.NoError(t, websocket.Message.Send(ws, "Alive")) {
+
+		assert.Equal(t, "Alive", <-received)
+	}
+}
+
+func TestWebSocketKeepaliveHandlerNotReceiving(t *testing.T) {
+
+	terminated := make(chan bool, 1)
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{
+		PingInterval: 10 * time.Millisecond,
+		PongTimeout:  50 * time.Millisecond,
+	}), func(c *Context) {
+
+		select {
+		case <-c.WebSocket.Done():
+
+			terminated <- true
+
+		case <-time.After(300 * time.Millisecond):
+
+			terminated <- false
+		}
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	defer ws.Close()
+
+	go func() {
+
+		var message string
+
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+	}()
+
+	if assert.NoError(t, websocket.Message.Send(ws, "Unread")) {
+
+		assert.False(t, <-terminated)
+	}
+}
+
+func TestWebSocketReceiveQueueOverflow(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{
+		PingInterval:     time.Minute,
+		ReceiveQueueSize: 1,
+	}), func(c *Context) {
+
+		<-c.Done()
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	defer ws.Close()
+
+	for i := 0; i < 3; i++ {
+
+		assert.NoError(t, websocket.Message.Send(ws, "Unread"))
+	}
+
+	if frame, err := ws.NewFrameReader(); assert.NoError(t, err) {
+
+		assert.Equal(t, byte(websocket.CloseFrame), frame.PayloadType())
+
+		if payload, err := ioutil.ReadAll(frame); assert.NoError(t, err) && assert.True(t, len(payload) >= 2) {
+
+			assert.Equal(t, ClosePolicyViolation, int(binary.BigEndian.Uint16(payload)))
+		}
+	}
+}
+
+func TestWebSocketMaxMessageSize(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{MaxMessageSize: 4}), func(c *Context) {
+
+		_, _, err := c.WebSocket.Receive()
+
+		assert.Equal(t, ErrMessageTooBig, err)
+	})
+
+	ts := httptest.NewServer(app)
+
+	ws := dialWebSocket(t, ts, "/ws/")
+
+	if assert.NoError(t, websocket.Message.Send(ws, "Too big")) {
+
+		if frame, err := ws.NewFrameReader(); assert.NoError(t, err) {
+
+			if payload, err := ioutil.ReadAll(frame); assert.NoError(t, err) && assert.True(t, len(payload) >= 2) {
+
+				assert.Equal(t, CloseMessageTooBig, int(binary.BigEndian.Uint16(payload)))
+			}
+		}
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{AllowOrigins: []string{"https://*.example.com"}}), func(c *Context) {
+
+		c.WebSocket.SendText("Welcome")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	for origin, code := range map[string]int{"https://evil.com": http.StatusForbidden, "https://app.example.com": http.StatusBadRequest} {
+
+		if req, err := http.NewRequest("GET", ts.URL+"/ws/", nil); assert.NoError(t, err) {
+
+			req.Header.Set("Origin", origin)
+
+			if res, err := client.Do(req); assert.NoError(t, err) {
+
+				assert.Equal(t, code, res.StatusCode)
+			}
+		}
+	}
+
+	url := strings.Replace(ts.URL, "http://", "ws://", 1) + "/ws/"
+
+	_, err := websocket.Dial(url, "", "https://evil.com")
+
+	assert.Error(t, err)
+
+	if ws, err := websocket.Dial(url, "", "https://app.example.com"); assert.NoError(t, err) {
+
+		var text string
+
+		if assert.NoError(t, websocket.Message.Receive(ws, &text)) {
+
+			assert.Equal(t, "Welcome", text)
+		}
+	}
+}
+
+func TestWebSocketHandshakeHook(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{
+		Handshake: func(c *Context) error {
+
+			if c.Request.URL.Query().Get("token") != "secret" {
+
+				return NewHTTPError(http.StatusUnauthorized, "", nil)
+			}
+
+			return nil
+		},
+	}), func(c *Context) {
+
+		c.WebSocket.SendText("Welcome")
+	})
+
+	ts := httptest.NewServer(app)
+
+	client := &http.Client{}
+
+	if res, err := client.Get(ts.URL + "/ws/"); assert.NoError(t, err) {
+
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	}
+
+	ws := dialWebSocket(t, ts, "/ws/?token=secret")
+
+	var text string
+
+	if assert.NoError(t, websocket.Message.Receive(ws, &text)) {
+
+		assert.Equal(t, "Welcome", text)
+	}
+}
+
+func TestWebSocketSubprotocol(t *testing.T) {
+
+	app := New()
+	app.WebSocket("/ws/", WebSocketOptions(WebSocketConfig{Protocols: []string{"chat.v1", "chat.v2"}}), func(c *Context) {
+
+		c.WebSocket.SendText(c.WebSocket.Protocol())
+	})
+
+	ts := httptest.NewServer(app)
+
+	for requested, expected := range map[string]string{"chat.v2, chat.v1": "chat.v1", "chat.v2": "chat.v2", "chat.v3": ""} {
+
+		config, err := websocket.NewConfig(strings.Replace(ts.URL, "http://", "ws://", 1)+"/ws/", ts.URL)
+
+		if !assert.NoError(t, err) {
+
+			return
+		}
+
+		config.Protocol = strings.Split(requested, ", ")
+
+		if ws, err := websocket.DialConfig(config); assert.NoError(t, err) {
+
+			var text string
+
+			if assert.NoError(t, websocket.Message.Receive(ws, &text)) {
+
+				assert.Equal(t, expected, text)
+			}
+
+			ws.Close()
+		}
+	}
+}